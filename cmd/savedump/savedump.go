@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+	"log"
+	"os"
+)
+
+// savedump prints any save file as indented JSON, whether it was written as JSON or binary and whether it is encrypted
+//
+//	go run ./cmd/savedump [-key password] [-o out.json] save.novella
+func main() {
+	key := flag.String("key", "", "the password the save is encrypted with, the default password is tried if it is empty")
+	out := flag.String("o", "", "the file to write the JSON to, it is printed if this is empty")
+	flag.Usage = func() {
+		_, _ = fmt.Fprintln(flag.CommandLine.Output(), "Usage: savedump [-key password] [-o out.json] save.novella")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fileBytes, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	dump, err := NFSave.DumpJSON(fileBytes, *key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		fmt.Println(string(dump))
+		return
+	}
+	err = os.WriteFile(*out, append(dump, '\n'), 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -7,6 +7,7 @@ go 1.22.1
 require (
 	fyne.io/fyne/v2 v2.4.5
 	github.com/google/uuid v1.1.2
+	golang.org/x/crypto v0.22.0
 	golang.org/x/sys v0.19.0
 )
 
@@ -324,6 +324,7 @@ func (p NFProject) Create(window fyne.Window) error {
 		"data/assets/video",
 		"data/assets/other",
 		"data/scenes",
+		"data/characters",
 		"internal/functions",
 		"internal/layouts",
 		"internal/widgets",
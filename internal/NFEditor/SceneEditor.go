@@ -17,12 +17,17 @@ import (
 	"fyne.io/fyne/v2/data/binding"
 	"github.com/google/uuid"
 
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFCharacter"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFConfig"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFFS"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFFunction"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFLayout"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFPrefab"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFScene"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFScript"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -66,6 +71,8 @@ var (
 	layouts   = make(map[string]NFObjects.AssetProperties)
 	widgets   = make(map[string]NFObjects.AssetProperties)
 
+	characters = make([]string, 0) //IDs of the characters in the project, offered for Character args
+
 	selectedScenePath string
 	selectedScene     *NFScene.Scene
 	selectedObject    interface{}
@@ -122,7 +129,60 @@ func loadAssets(initialPath string) error {
 	return err
 }
 
+// loadCharacters finds the IDs of all characters in the project so they can be picked for Character args
+func loadCharacters(initialPath string) error {
+	characters = make([]string, 0)
+	err := filepath.WalkDir(filepath.Clean(initialPath), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != NFCharacter.Extension {
+			return nil
+		}
+		character, err := NFCharacter.Load(path)
+		if err != nil {
+			log.Println("Error loading character: ", err)
+			return nil
+		}
+		if slices.Contains(characters, character.ID) {
+			log.Println("Character ID already exists: " + character.ID)
+			log.Println("To prevent this, make sure all character IDs are unique")
+			return nil
+		}
+		characters = append(characters, character.ID)
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	slices.Sort(characters)
+	return err
+}
+
+// projectFS is the name the open project's folder is added to NFFS with
+const projectFS = "project"
+
+// registerProjectFiles registers the scenes and prefabs of the open project,
+// so scenes that instance prefabs or extend other scenes can be validated and previewed
+func registerProjectFiles() {
+	NFFS.SetFS(projectFS, os.DirFS(filepath.Dir(ActiveProject.Info.Path)))
+	NFScene.SceneMap = map[string]string{}
+	NFPrefab.PrefabMap = map[string]string{}
+	//The files change while the project is edited, so nothing read before is kept
+	NFScene.ClearSceneCache()
+	NFPrefab.ClearCache()
+	err := NFScene.RegisterAll("data/scenes")
+	if err != nil {
+		log.Println("Error registering the project's scenes: ", err)
+	}
+	err = NFPrefab.RegisterAll(NFPrefab.Directory)
+	if err != nil {
+		log.Println("Error registering the project's prefabs: ", err)
+	}
+}
+
 func regenSceneMap(initialPath string) error {
+	registerProjectFiles()
 
 	//Nil the maps
 	newSceneTreeData := make(map[string][]string)
@@ -362,6 +422,14 @@ func UpdateMenuBar(window fyne.Window) {
 		}
 	})
 	editMenu.Items = append(editMenu.Items, previewSceneItem)
+	importScriptItem := fyne.NewMenuItem("Import Script", func() {
+		importScript(window)
+	})
+	editMenu.Items = append(editMenu.Items, importScriptItem)
+	newCharacterItem := fyne.NewMenuItem("New Character", func() {
+		newCharacter(window)
+	})
+	editMenu.Items = append(editMenu.Items, newCharacterItem)
 	runGameItem := fyne.NewMenuItem("Run Game", func() {
 		//TODO: Add in code to run the game
 	})
@@ -379,11 +447,84 @@ func UpdateMenuBar(window fyne.Window) {
 	window.SetMainMenu(mainMenu)
 }
 
+// importScript compiles a screenplay script chosen by the user in to scenes, saved in a folder named after the script
+func importScript(window fyne.Window) {
+	fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		//If the reader is nil, then the user canceled the dialog and nothing should happen
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+		script, err := NFScript.Parse(reader.URI().Name(), reader)
+		if err != nil {
+			log.Println("Error parsing script: ", err)
+			dialog.ShowError(err, window)
+			return
+		}
+		scenes, err := script.Compile()
+		if err != nil {
+			log.Println("Error compiling script: ", err)
+			dialog.ShowError(err, window)
+			return
+		}
+		scenesFolder := filepath.Join(filepath.Dir(ActiveProject.Info.Path), "data/scenes/")
+		err = NFScript.SaveAll(scenes, filepath.Join(scenesFolder, script.Name))
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		err = regenSceneMap(filepath.Clean(scenesFolder))
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		dialog.ShowInformation("Script Imported", fmt.Sprintf("Created %d scenes starting at %s", len(scenes), scenes[0].Name), window)
+	}, window)
+	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{NFScript.Extension}))
+	fileDialog.Show()
+}
+
+// newCharacter asks for an ID and creates a character file with the default styling in the project's characters folder
+func newCharacter(window fyne.Window) {
+	idEntry := widget.NewEntry()
+	idEntry.Validator = func(s string) error {
+		if slices.Contains(characters, s) {
+			return NFError.NewErrKeyAlreadyExists(s)
+		}
+		return NFCharacter.New(s).Validate()
+	}
+	dialog.ShowForm("New Character", "Create", "Cancel", []*widget.FormItem{widget.NewFormItem("ID", idEntry)}, func(b bool) {
+		if !b {
+			return
+		}
+		charactersFolder := filepath.Join(filepath.Dir(ActiveProject.Info.Path), NFCharacter.Directory)
+		err := NFCharacter.New(idEntry.Text).Save(charactersFolder)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		err = loadCharacters(charactersFolder)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		dialog.ShowInformation("Character Created", "Created "+idEntry.Text+NFCharacter.Extension+" in "+charactersFolder, window)
+	}, window)
+}
+
 func CreateSceneProperties(window fyne.Window) fyne.CanvasObject {
 	err := loadAssets(filepath.Join(ActiveProject.Info.Path, "data", "assets"))
 	if err != nil {
 		dialog.ShowError(err, window)
 	}
+	err = loadCharacters(filepath.Join(filepath.Dir(ActiveProject.Info.Path), NFCharacter.Directory))
+	if err != nil {
+		dialog.ShowError(err, window)
+	}
 
 	//If any are 0 run the export registered functions by creating it via template
 
@@ -1000,12 +1141,15 @@ func CreateScenePreview(window fyne.Window) fyne.CanvasObject {
 		// Put an empty widget in the preview
 		preview.Objects = []fyne.CanvasObject{}
 
-		// Parse the current scene
+		// Parse the current scene, its stages replace those of the last preview
+		swap := NFScene.BeginSceneSwap()
 		scene, err := selectedScene.Parse(window)
 		if err != nil {
+			swap.Discard()
 			log.Println(err)
 			dialog.ShowError(err, window)
 		} else {
+			swap.Commit()
 			preview.Add(scene)
 		}
 
@@ -1023,7 +1167,7 @@ func updateSceneObjects(tree *widget.Tree) error {
 	childrenMap, count := selectedScene.FetchAll()
 	log.Println("Scene has", count, "objects")
 	err := selectedScene.Validate()
-	if err != nil {
+	if errors.Is(err, NFError.ErrCriticalSceneValidation) {
 		selectedScene.MakeId()
 		return updateSceneObjects(tree)
 	}
@@ -1292,6 +1436,17 @@ func CreateParamItem(key string, val interface{}, parentObject NFData.CoupledObj
 	var formObject fyne.CanvasObject
 	switch valType {
 	case NFData.FloatType, NFData.IntType, NFData.StringType, NFData.BooleanType:
+		if key == "Character" && valType == NFData.StringType {
+			//Characters are referenced by ID, so the IDs in the project are offered
+			characterEntry := widget.NewSelectEntry(characters)
+			characterEntry.SetText(fmt.Sprintf("%v", val))
+			characterEntry.OnChanged = func(s string) {
+				parentObject.Set(key, s)
+				changesMade = true
+			}
+			formObject = characterEntry
+			break
+		}
 		entry := widget.NewEntry()
 		entry.SetText(fmt.Sprintf("%v", val))
 		entry.OnChanged = func(s string) {
@@ -6,12 +6,14 @@ import (
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFLayout"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFLayout/DefaultLayouts"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget/CalsWidgets"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget/DefaultWidgets"
 )
 
 func init() {
 	DefaultFunctions.Import()
 	DefaultWidgets.Import()
+	CalsWidgets.Import()
 	DefaultLayouts.Import()
 	//Add some form of function from the asset pack you want to import here
 	//i.e ExampleAssetPack.Import()
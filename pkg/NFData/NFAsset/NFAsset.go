@@ -0,0 +1,174 @@
+package NFAsset
+
+import (
+	"errors"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Preloader reads and decodes the asset at the path so it is ready before it is shown or played,
+// it returns NFError.ErrNotFound if there is no asset at the path
+type Preloader func(path string) (interface{}, error)
+
+// DefaultCacheSize is the number of assets the cache holds until SetCacheSize is called
+const DefaultCacheSize = 64
+
+// cache holds the preloaded assets by the path they were preloaded with
+var cache = NewLRU[string, interface{}](DefaultCacheSize)
+
+// preloaders holds the registered preloaders by the lowercase file extension they load
+var preloaders = map[string]Preloader{}
+var preloadersLock sync.RWMutex
+
+// loading holds a channel for each path that is being preloaded, it is closed once the asset is cached
+var loading = map[string]chan struct{}{}
+var loadingLock sync.Mutex
+
+// RegisterPreloader registers the preloader for files with the extension such as ".png", extensions must be unique
+func RegisterPreloader(extension string, preloader Preloader) error {
+	extension = strings.ToLower(extension)
+	if !strings.HasPrefix(extension, ".") {
+		return NFError.NewErrInvalidArgument("extension", "the extension must start with a dot")
+	}
+	preloadersLock.Lock()
+	defer preloadersLock.Unlock()
+	if _, ok := preloaders[extension]; ok {
+		return NFError.NewErrKeyAlreadyExists("preloader for " + extension)
+	}
+	preloaders[extension] = preloader
+	return nil
+}
+
+// GetPreloaders returns the extensions that have a preloader, sorted
+func GetPreloaders() []string {
+	preloadersLock.RLock()
+	defer preloadersLock.RUnlock()
+	extensions := make([]string, 0, len(preloaders))
+	for extension := range preloaders {
+		extensions = append(extensions, extension)
+	}
+	slices.Sort(extensions)
+	return extensions
+}
+
+// preloaderFor returns the preloader for the extension of the path
+func preloaderFor(path string) (Preloader, bool) {
+	preloadersLock.RLock()
+	defer preloadersLock.RUnlock()
+	preloader, ok := preloaders[strings.ToLower(filepath.Ext(path))]
+	return preloader, ok
+}
+
+// Preloadable returns true if there is a preloader for the extension of the path
+func Preloadable(path string) bool {
+	_, ok := preloaderFor(path)
+	return ok
+}
+
+// Get returns the preloaded asset for the path
+func Get(path string) (interface{}, bool) {
+	return cache.Get(path)
+}
+
+// Cached returns true if the asset for the path has been preloaded
+func Cached(path string) bool {
+	return cache.Contains(path)
+}
+
+// Preload runs the preloader for the path and caches the asset, assets that are already cached are not loaded again.
+// If the path is being preloaded on another goroutine this waits for it instead
+func Preload(path string) error {
+	preloader, ok := preloaderFor(path)
+	if !ok {
+		return NFError.NewErrNotImplemented("preloading " + filepath.Ext(path) + " files")
+	}
+	for {
+		loadingLock.Lock()
+		if cache.Contains(path) {
+			loadingLock.Unlock()
+			return nil
+		}
+		wait, ok := loading[path]
+		if !ok {
+			break
+		}
+		loadingLock.Unlock()
+		<-wait
+	}
+	done := make(chan struct{})
+	loading[path] = done
+	loadingLock.Unlock()
+	defer func() {
+		loadingLock.Lock()
+		delete(loading, path)
+		loadingLock.Unlock()
+		close(done)
+	}()
+
+	asset, err := preloader(path)
+	if err != nil {
+		return err
+	}
+	cache.Add(path, asset)
+	return nil
+}
+
+// PreloadAll preloads the paths on as many goroutines as there are CPUs and waits for them all,
+// progress is called after each path with how many have finished and it may be nil.
+// Paths with no asset are skipped, the other errors are returned joined
+func PreloadAll(paths []string, progress func(done, total int)) error {
+	var fullErr error
+	var lock sync.Mutex
+	var wait sync.WaitGroup
+	finished := 0
+	queue := make(chan string)
+	for i := 0; i < min(runtime.NumCPU(), len(paths)); i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for path := range queue {
+				err := Preload(path)
+				lock.Lock()
+				if err != nil && !errors.Is(err, NFError.ErrNotFound) {
+					fullErr = errors.Join(fullErr, err)
+				}
+				finished++
+				if progress != nil {
+					progress(finished, len(paths))
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	for _, path := range paths {
+		queue <- path
+	}
+	close(queue)
+	wait.Wait()
+	return fullErr
+}
+
+// Uncached returns the paths that have a preloader and have not been preloaded, without duplicates
+func Uncached(paths []string) []string {
+	uncached := make([]string, 0)
+	for _, path := range paths {
+		if Preloadable(path) && !cache.Contains(path) && !slices.Contains(uncached, path) {
+			uncached = append(uncached, path)
+		}
+	}
+	return uncached
+}
+
+// SetCacheSize changes how many assets are kept, the least recently used assets are dropped first
+func SetCacheSize(size int) {
+	cache.Resize(size)
+}
+
+// Clear drops every preloaded asset, such as when the assets on disk have changed
+func Clear() {
+	cache.Clear()
+}
@@ -0,0 +1,64 @@
+package NFAsset
+
+import (
+	"fyne.io/fyne/v2/canvas"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+)
+
+// ImageDirectory is where images are looked for when they are not found at their own path
+const ImageDirectory = "assets/image/"
+
+func init() {
+	for _, extension := range []string{".png", ".jpg", ".jpeg", ".gif"} {
+		_ = RegisterPreloader(extension, preloadImage)
+	}
+}
+
+// ImagePath returns the path of the image, or of the image in the ImageDirectory if there is nothing at the path
+func ImagePath(path string) (string, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		path = ImageDirectory + path
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return "", NFError.NewErrNotFound("no image at " + path)
+		}
+	}
+	return path, nil
+}
+
+// preloadImage decodes the image so it does not have to be decoded while it is being drawn
+func preloadImage(path string) (interface{}, error) {
+	path, err := ImagePath(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	decoded, _, err := image.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// Image returns a canvas.Image for the path, which is looked for like ImagePath.
+// If the image has been preloaded the decoded image is used, otherwise fyne loads it from the file when it is drawn
+func Image(path string) (*canvas.Image, error) {
+	if asset, ok := Get(path); ok {
+		if decoded, ok := asset.(image.Image); ok {
+			return canvas.NewImageFromImage(decoded), nil
+		}
+	}
+	resolved, err := ImagePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return canvas.NewImageFromFile(resolved), nil
+}
@@ -0,0 +1,117 @@
+package NFAsset
+
+import (
+	"container/list"
+	"sync"
+)
+
+// LRU is a cache that holds up to its size of values, once it is full adding a value drops the least recently used one.
+// It is safe to use from multiple goroutines
+type LRU[K comparable, V any] struct {
+	size    int
+	order   *list.List
+	entries map[K]*list.Element
+	lock    sync.Mutex
+}
+
+// lruEntry is the value stored in each element of the order list
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewLRU creates an LRU that holds up to size values, a size below 1 holds nothing
+func NewLRU[K comparable, V any](size int) *LRU[K, V] {
+	return &LRU[K, V]{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[K]*list.Element),
+	}
+}
+
+// Get returns the value for the key and marks it as the most recently used
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry[K, V]).value, true
+}
+
+// Contains returns true if the key is in the cache without marking it as used
+func (c *LRU[K, V]) Contains(key K) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, ok := c.entries[key]
+	return ok
+}
+
+// Add sets the value for the key as the most recently used, dropping the least recently used values if the cache is full
+func (c *LRU[K, V]) Add(key K, value V) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+	c.trim()
+}
+
+// Remove drops the value for the key
+func (c *LRU[K, V]) Remove(key K) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of values in the cache
+func (c *LRU[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.order.Len()
+}
+
+// Keys returns the keys in the cache from the most to the least recently used
+func (c *LRU[K, V]) Keys() []K {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	keys := make([]K, 0, c.order.Len())
+	for element := c.order.Front(); element != nil; element = element.Next() {
+		keys = append(keys, element.Value.(*lruEntry[K, V]).key)
+	}
+	return keys
+}
+
+// Resize changes how many values the cache holds, dropping the least recently used values if it holds too many
+func (c *LRU[K, V]) Resize(size int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.size = size
+	c.trim()
+}
+
+// Clear drops every value
+func (c *LRU[K, V]) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.order.Init()
+	c.entries = make(map[K]*list.Element)
+}
+
+// trim drops the least recently used values until the cache fits its size, the caller must hold the lock
+func (c *LRU[K, V]) trim() {
+	for c.order.Len() > max(c.size, 0) {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
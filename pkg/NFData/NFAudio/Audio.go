@@ -9,6 +9,7 @@ import (
 	"github.com/faiface/beep/effects"
 	"github.com/faiface/beep/mp3"
 	"github.com/faiface/beep/speaker"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFAsset"
 
 	"os"
 	"time"
@@ -33,6 +34,11 @@ type SpeakerTrack struct {
 	muteChannel         chan bool
 	unmuteChannel       chan bool
 	masterVolumeChannel chan float64
+	//file, volume, speed and loops are what the track was last played with, so it can be played again when a save is resumed
+	file   string
+	volume float64
+	speed  float64
+	loops  int
 }
 
 func NewSpeakerTrack(name string) (*SpeakerTrack, error) {
@@ -69,6 +75,8 @@ func NewSpeakerTrack(name string) (*SpeakerTrack, error) {
 func (s *SpeakerTrack) PlayAudioFromBytes(data []byte, volume float64, speed float64, loops int) error {
 	reader := bytes.NewReader(data)
 	rc := io.NopCloser(reader)
+	//Audio played from bytes has no file to play again from, so it is not kept in saves
+	s.file = ""
 	return s.playAudio(rc, volume, speed, loops)
 }
 
@@ -77,11 +85,20 @@ func (s *SpeakerTrack) PlayAudioFromBytes(data []byte, volume float64, speed flo
 // The speed should be a ratio for the speed adjustment, with 1 using the original speed of the file.
 // The loops option allows for the audio track to be repeated indefinitely. Choose -1 for infinitely looping audio
 // A loops value of 0 and 1 have the same functionality, playing the audio once.
+//
+// If the file was preloaded with NFAsset it is played from memory rather than read from the disk
 func (s *SpeakerTrack) PlayAudioFromFile(file string, volume float64, speed float64, loops int) error {
+	if asset, ok := NFAsset.Get(file); ok {
+		if data, ok := asset.([]byte); ok {
+			s.file = file
+			return s.playAudio(io.NopCloser(bytes.NewReader(data)), volume, speed, loops)
+		}
+	}
 	f, err := os.Open(file)
 	if err != nil {
 		return err
 	}
+	s.file = file
 	return s.playAudio(f, volume, speed, loops)
 }
 
@@ -131,6 +148,7 @@ func (s *SpeakerTrack) playAudio(rc io.ReadCloser, volume float64, speed float64
 	// Allow for playing and pausing the audio track
 	ctrl := &beep.Ctrl{Streamer: speedStreamer, Paused: false}
 
+	s.volume, s.speed, s.loops = volume, speed, loops
 	s.state = "playing"
 
 	// Play the audio track.
@@ -141,6 +159,7 @@ func (s *SpeakerTrack) playAudio(rc io.ReadCloser, volume float64, speed float64
 	for {
 		select {
 		case <-s.done:
+			s.state = "done"
 			return nil
 		case <-s.pauseChannel:
 			speaker.Lock()
@@ -205,6 +224,7 @@ func (s *SpeakerTrack) ResumeAudio() {
 // Change the volume of the audio track.
 func (s *SpeakerTrack) ChangeVolume(volume float64) {
 	s.volumeChange <- volume
+	s.volume = volume
 }
 
 // Changes master volume of all audio tracks. The default master volume is 5.
@@ -0,0 +1,110 @@
+package NFAudio
+
+import (
+	"encoding/json"
+	"errors"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFAsset"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFCharacter"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+	"io/fs"
+	"log"
+	"os"
+)
+
+// AudioStateSection is the section of a save that holds the tracks that were playing
+const AudioStateSection = "Audio"
+
+func init() {
+	_ = NFSave.RegisterStateProvider(AudioStateSection, NFSave.StateFuncs{Save: saveAudioState, Load: loadAudioState})
+	_ = NFAsset.RegisterPreloader(".mp3", preloadAudio)
+	NFCharacter.SetVoicePlayer(playVoice)
+}
+
+// playVoice plays a character's voice line, stopping the line that is still playing on the track
+func playVoice(name, path string, volume, speed float64) error {
+	track, ok := SpeakerTracks[name]
+	if !ok || track == nil {
+		var err error
+		track, err = NewSpeakerTrack(name)
+		if err != nil {
+			return err
+		}
+	}
+	if track.state == "playing" || track.state == "paused" {
+		track.ClearAudio()
+	}
+	//Playing blocks until the line ends so it is played on its own goroutine
+	go func() {
+		err := track.PlayAudioFromFile(path, volume, speed, 0)
+		if err != nil {
+			log.Println("Error playing voice line ", path, " on track ", name, ": ", err)
+		}
+	}()
+	return nil
+}
+
+// preloadAudio reads the audio file in to memory, it is decoded as it plays so only the reading is done ahead of time
+func preloadAudio(path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, NFError.NewErrNotFound("no audio at " + path)
+	}
+	return data, err
+}
+
+// trackState is a track that was playing when a save was written, it is played again from the start when the save is resumed
+type trackState struct {
+	Name   string  `json:"Name"`
+	File   string  `json:"File"`
+	Volume float64 `json:"Volume"`
+	Speed  float64 `json:"Speed"`
+	Loops  int     `json:"Loops"`
+}
+
+// saveAudioState returns the tracks that are playing from a file
+func saveAudioState() (interface{}, error) {
+	tracks := make([]trackState, 0)
+	for name, track := range SpeakerTracks {
+		if track == nil || track.state != "playing" || track.file == "" {
+			continue
+		}
+		tracks = append(tracks, trackState{Name: name, File: track.file, Volume: track.volume, Speed: track.speed, Loops: track.loops})
+	}
+	return tracks, nil
+}
+
+// loadAudioState clears every track that is playing and plays the tracks in the save, saves without the section leave the audio as it is
+func loadAudioState(data json.RawMessage) error {
+	if data == nil {
+		return nil
+	}
+	tracks := make([]trackState, 0)
+	err := json.Unmarshal(data, &tracks)
+	if err != nil {
+		return err
+	}
+	for _, track := range SpeakerTracks {
+		//Only tracks that are playing or paused are listening for the clear
+		if track != nil && (track.state == "playing" || track.state == "paused") {
+			track.ClearAudio()
+		}
+	}
+	for _, saved := range tracks {
+		track, ok := SpeakerTracks[saved.Name]
+		if !ok || track == nil {
+			track, err = NewSpeakerTrack(saved.Name)
+			if err != nil {
+				return err
+			}
+		}
+		//Playing blocks until the track ends so each track is played on its own goroutine
+		go func(track *SpeakerTrack, saved trackState) {
+			err := track.PlayAudioFromFile(saved.File, saved.Volume, saved.Speed, saved.Loops)
+			if err != nil {
+				log.Println("Error playing ", saved.File, " on track ", saved.Name, ": ", err)
+			}
+		}(track, saved)
+	}
+	return nil
+}
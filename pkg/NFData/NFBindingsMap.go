@@ -1,6 +1,8 @@
 package NFData
 
 import (
+	"errors"
+	"fmt"
 	"fyne.io/fyne/v2/data/binding"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
 	"reflect"
@@ -184,3 +186,36 @@ func (nfb *NFBindingMap) CreateBinding(key string, value interface{}) error {
 	}
 	return nil
 }
+
+// UpdateBinding sets the value of every existing binding with the given key, converting the value to the type of the binding
+//
+// Bindings that do not exist are not created, and a nil value resets the bindings to the zero value of their type
+func (nfb *NFBindingMap) UpdateBinding(key string, value interface{}) error {
+	nfb.mu.RLock()
+	defer nfb.mu.RUnlock()
+
+	var err error
+	if b, ok := nfb.intBindings[key]; ok {
+		i, _ := ToInt(value)
+		err = errors.Join(err, b.Set(i))
+	}
+	if b, ok := nfb.floatBindings[key]; ok {
+		f, _ := ToFloat(value)
+		err = errors.Join(err, b.Set(f))
+	}
+	if b, ok := nfb.boolBindings[key]; ok {
+		boolean, _ := value.(bool)
+		err = errors.Join(err, b.Set(boolean))
+	}
+	if b, ok := nfb.stringBindings[key]; ok {
+		str := ""
+		if value != nil {
+			str = fmt.Sprint(value)
+		}
+		err = errors.Join(err, b.Set(str))
+	}
+	if b, ok := nfb.untypedBindings[key]; ok {
+		err = errors.Join(err, b.Set(value))
+	}
+	return err
+}
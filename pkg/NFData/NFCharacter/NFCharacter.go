@@ -0,0 +1,298 @@
+// Package NFCharacter holds the characters of the game, each one defined once in a .NFCharacter file
+// and referenced by ID from dialogue widgets instead of copying their name, colors and sprites in to every scene
+package NFCharacter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFFS"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFStyling"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Extension is the file extension used for character files
+const Extension = ".NFCharacter"
+
+// Directory is where the characters of a game are kept, relative to the game directory
+const Directory = "data/characters"
+
+// CharacterMap maps character IDs to the path of their file
+var CharacterMap = map[string]string{}
+
+var cache = map[string]*Character{}
+var cacheLock sync.Mutex
+
+var validID = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+
+// Voice holds the defaults used when playing a character's voice lines
+type Voice struct {
+	// Track is the name of the speaker track the voice lines are played on
+	Track string `json:"Track"`
+	// Directory is where the character's voice lines are kept, lines are looked up by file name inside it
+	Directory string  `json:"Directory"`
+	Volume    float64 `json:"Volume"`
+	Speed     float64 `json:"Speed"`
+}
+
+// Character is a speaker that can be referenced by ID from dialogue widgets
+type Character struct {
+	ID string `json:"ID"`
+	// Name is the display name, it can be a string or an NFExpression such as {"Expression": "Save.PlayerName"}
+	Name interface{} `json:"Name"`
+	// The colors are hex strings such as "#808080FF", an empty string keeps the widget's default
+	NameColor       string              `json:"NameColor"`
+	NameStrokeColor string              `json:"NameStrokeColor"`
+	NameStyle       NFStyling.NFStyling `json:"NameStyle"`
+	Voice           Voice               `json:"Voice"`
+	// Expressions maps expression names to image paths, DefaultExpression is used when no expression is asked for
+	Expressions       map[string]string `json:"Expressions"`
+	DefaultExpression string            `json:"DefaultExpression"`
+}
+
+// New creates a character with the default styling and voice
+func New(id string) *Character {
+	return &Character{
+		ID:          id,
+		Name:        id,
+		NameStyle:   NFStyling.NewTextStyling(),
+		Voice:       Voice{Track: "Voice", Volume: 1, Speed: 1},
+		Expressions: map[string]string{},
+	}
+}
+
+// Validate checks that the character can be saved and referenced
+func (c *Character) Validate() error {
+	if !validID.MatchString(c.ID) {
+		return NFError.NewErrInvalidArgument("ID", "character IDs can only contain letters, numbers, _ and -")
+	}
+	if c.DefaultExpression != "" {
+		if _, ok := c.Expressions[c.DefaultExpression]; !ok {
+			return NFError.NewErrInvalidArgument("DefaultExpression", "the character has no expression "+c.DefaultExpression)
+		}
+	}
+	return nil
+}
+
+// DisplayName returns the name shown on the name plate, evaluating it if it is an NFExpression
+func (c *Character) DisplayName() (string, error) {
+	name, err := NFData.Evaluate(c.Name)
+	if err != nil {
+		return "", err
+	}
+	if name == nil {
+		return c.ID, nil
+	}
+	return fmt.Sprint(name), nil
+}
+
+// Expression returns the image path of the named expression, or of the default expression if the name is empty
+func (c *Character) Expression(name string) (string, error) {
+	if name == "" {
+		name = c.DefaultExpression
+	}
+	path, ok := c.Expressions[name]
+	if !ok {
+		return "", NFError.NewErrNotFound("character " + c.ID + " has no expression " + name)
+	}
+	return path, nil
+}
+
+// GetExpressions returns the names of the character's expressions in order
+func (c *Character) GetExpressions() []string {
+	names := make([]string, 0, len(c.Expressions))
+	for name := range c.Expressions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// VoicePath returns the path of a voice line file in the character's voice directory
+func (c *Character) VoicePath(line string) string {
+	if c.Voice.Directory == "" {
+		return line
+	}
+	return filepath.ToSlash(filepath.Join(c.Voice.Directory, line))
+}
+
+// VoicePlayer plays the audio file at path on the named speaker track, it returns once the file has started playing.
+// NFAudio registers one when it is imported, see SetVoicePlayer
+type VoicePlayer func(track, path string, volume, speed float64) error
+
+var voicePlayer VoicePlayer
+var voicePlayerLock sync.RWMutex
+
+// SetVoicePlayer sets what PlayVoice plays voice lines with, this lets NFCharacter play audio without depending on NFAudio
+func SetVoicePlayer(player VoicePlayer) {
+	voicePlayerLock.Lock()
+	defer voicePlayerLock.Unlock()
+	voicePlayer = player
+}
+
+// PlayVoice plays a voice line from the character's voice directory on its voice track with its volume and speed,
+// an empty line plays nothing
+func (c *Character) PlayVoice(line string) error {
+	if line == "" {
+		return nil
+	}
+	voicePlayerLock.RLock()
+	player := voicePlayer
+	voicePlayerLock.RUnlock()
+	if player == nil {
+		return NFError.NewErrNotImplemented("playing voice lines without a voice player, import NFAudio")
+	}
+	return player(c.Voice.Track, c.VoicePath(line), c.Voice.Volume, c.Voice.Speed)
+}
+
+// Save writes the character to dir/ID.NFCharacter
+func (c *Character) Save(dir string) error {
+	err := c.Validate()
+	if err != nil {
+		return err
+	}
+	if filepath.Ext(dir) != "" {
+		dir = filepath.Dir(dir)
+	}
+	path := filepath.Join(dir, c.ID+Extension)
+	jsonBytes, err := json.MarshalIndent(c, "", "\t")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, jsonBytes, 0755)
+}
+
+// Load loads a character from a file on disk, this is used by the editor, games should use Get
+func Load(path string) (*Character, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decode(path, data)
+}
+
+func decode(path string, data []byte) (*Character, error) {
+	c := New("")
+	err := json.Unmarshal(data, c)
+	if err != nil {
+		return nil, NFError.NewErrFileGet(path, err.Error())
+	}
+	if c.ID == "" {
+		c.ID = strings.TrimSuffix(filepath.Base(path), Extension)
+	}
+	err = c.Validate()
+	if err != nil {
+		return nil, errors.Join(NFError.NewErrFileGet(path, "invalid character"), err)
+	}
+	return c, nil
+}
+
+// Register registers a character with the CharacterMap
+func Register(id, path string) error {
+	path = filepath.Clean(path)
+	if !fs.ValidPath(path) {
+		return errors.New("invalid path")
+	}
+	if _, ok := CharacterMap[id]; ok {
+		return NFError.NewErrKeyAlreadyExists(id)
+	}
+	CharacterMap[id] = path
+	return nil
+}
+
+// RegisterAll registers all characters by walking both the embedded and local filesystems
+//
+// Characters are registered under the ID in their file, the same ID the editor lists them by,
+// which is the file name only if the file has no ID. Files that can not be read are logged and skipped.
+// Like RegisterAll for scenes this should only be called once at the start of the program
+func RegisterAll(path string) error {
+	config := NFFS.NewConfiguration(true)
+	return NFFS.Walk(path, config, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, Extension) {
+			return nil
+		}
+		data, err := NFFS.ReadFile(path, config)
+		if err != nil {
+			log.Println("Error reading character: ", err)
+			return nil
+		}
+		c, err := decode(path, data)
+		if err != nil {
+			log.Println("Error loading character: ", err)
+			return nil
+		}
+		id := c.ID
+		if oldPath, ok := CharacterMap[id]; ok {
+			log.Println("Character already registered: ", id, " at ", oldPath)
+			log.Println("Make sure characters have unique IDs")
+		} else {
+			CharacterMap[id] = path
+		}
+		return nil
+	})
+}
+
+// GetIDs returns the IDs of all registered characters in order
+func GetIDs() []string {
+	ids := make([]string, 0, len(CharacterMap))
+	for id := range CharacterMap {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Get returns the registered character with the ID, characters are only read from the filesystem once
+func Get(id string, config ...NFFS.Configuration) (*Character, error) {
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if c, ok := cache[id]; ok {
+		return c, nil
+	}
+	path, ok := CharacterMap[id]
+	if !ok {
+		return nil, NFError.NewErrNotFound("character not registered: " + id)
+	}
+	if len(config) == 0 {
+		config = append(config, NFFS.NewConfiguration(true))
+	}
+	file, err := NFFS.Open(path, config[0])
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	c, err := decode(path, data)
+	if err != nil {
+		return nil, err
+	}
+	cache[id] = c
+	return c, nil
+}
+
+// ClearCache forgets the characters loaded by Get so they are read again, this is used when the files change
+func ClearCache() {
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	cache = map[string]*Character{}
+}
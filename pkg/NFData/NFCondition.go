@@ -0,0 +1,265 @@
+package NFData
+
+import (
+	"errors"
+	"fmt"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Operator is a comparison used by conditions to compare a variable with a value
+type Operator string
+
+const (
+	OpEqual          Operator = "=="
+	OpNotEqual       Operator = "!="
+	OpLess           Operator = "<"
+	OpLessOrEqual    Operator = "<="
+	OpGreater        Operator = ">"
+	OpGreaterOrEqual Operator = ">="
+	OpContains       Operator = "contains"
+	OpNotContains    Operator = "!contains"
+	OpIsSet          Operator = "isSet"
+	OpNotSet         Operator = "!isSet"
+)
+
+// GetOperators returns all the operators that can be used in a condition
+func GetOperators() []Operator {
+	return []Operator{OpEqual, OpNotEqual, OpLess, OpLessOrEqual, OpGreater, OpGreaterOrEqual, OpContains, OpNotContains, OpIsSet, OpNotSet}
+}
+
+// AsMap returns the value as a CustomMap if it is a map[string]interface{}, CustomMap or *NFInterfaceMap
+//
+// This is needed as maps loaded from json are never NFInterfaceMaps
+func AsMap(value interface{}) (CustomMap, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case CustomMap:
+		return v, true
+	case *NFInterfaceMap:
+		if v == nil {
+			return nil, false
+		}
+		return v.Copy().(*NFInterfaceMap).Data, true
+	default:
+		return nil, false
+	}
+}
+
+// AsReference converts a map with a Location and Key to an NFReference, returning false if the value is not a reference
+func AsReference(value interface{}) (NFReference, bool) {
+	if ref, ok := value.(NFReference); ok {
+		return ref, true
+	}
+	m, ok := AsMap(value)
+	if !ok {
+		return NFReference{}, false
+	}
+	location, ok := m["Location"].(string)
+	if !ok || !slices.Contains(GetRefTypes(), Type(location)) {
+		return NFReference{}, false
+	}
+	key, ok := m["Key"].(string)
+	if !ok || key == "" {
+		return NFReference{}, false
+	}
+	return NewRef(Type(location), key), true
+}
+
+// Resolve returns the value the reference points to if value is a reference, or its result if value is an NFExpression,
+// otherwise it returns value unchanged
+func Resolve(value interface{}) (interface{}, error) {
+	if ref, ok := AsReference(value); ok {
+		return ref.UnTypedGet()
+	}
+	return Evaluate(value)
+}
+
+// Compare compares left with right using the operator
+//
+// Numbers are compared as floats so ints and floats loaded from json can be compared with each other,
+// contains works on strings, slices and map keys. isSet and !isSet only check if left is not nil
+func Compare(left interface{}, op Operator, right interface{}) (bool, error) {
+	switch op {
+	case OpIsSet:
+		return left != nil, nil
+	case OpNotSet:
+		return left == nil, nil
+	case OpEqual:
+		return equal(left, right), nil
+	case OpNotEqual:
+		return !equal(left, right), nil
+	case OpContains:
+		return contains(left, right)
+	case OpNotContains:
+		ok, err := contains(left, right)
+		return !ok, err
+	case OpLess, OpLessOrEqual, OpGreater, OpGreaterOrEqual:
+		cmp, err := order(left, right)
+		if err != nil {
+			return false, err
+		}
+		switch op {
+		case OpLess:
+			return cmp < 0, nil
+		case OpLessOrEqual:
+			return cmp <= 0, nil
+		case OpGreater:
+			return cmp > 0, nil
+		default:
+			return cmp >= 0, nil
+		}
+	default:
+		return false, NFError.NewErrInvalidArgument("Operator", "unknown operator "+string(op))
+	}
+}
+
+// equal checks if two values are equal, numbers of different types are equal if their values are
+func equal(left, right interface{}) bool {
+	leftFloat, leftOk := ToFloat(left)
+	rightFloat, rightOk := ToFloat(right)
+	if leftOk && rightOk {
+		return leftFloat == rightFloat
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// order returns -1, 0 or 1 depending on whether left is less than, equal to or greater than right
+func order(left, right interface{}) (int, error) {
+	leftFloat, leftOk := ToFloat(left)
+	rightFloat, rightOk := ToFloat(right)
+	if leftOk && rightOk {
+		switch {
+		case leftFloat < rightFloat:
+			return -1, nil
+		case leftFloat > rightFloat:
+			return 1, nil
+		default:
+			return 0, nil
+		}
+	}
+	leftString, leftOk := left.(string)
+	rightString, rightOk := right.(string)
+	if leftOk && rightOk {
+		return strings.Compare(leftString, rightString), nil
+	}
+	return 0, NFError.NewErrTypeMismatch(fmt.Sprintf("%T", left), fmt.Sprintf("%T", right))
+}
+
+// contains checks if left contains right, left can be a string, slice or map
+func contains(left, right interface{}) (bool, error) {
+	if str, ok := left.(string); ok {
+		sub, ok := right.(string)
+		if !ok {
+			return false, NFError.NewErrTypeMismatch("string", fmt.Sprintf("%T", right))
+		}
+		return strings.Contains(str, sub), nil
+	}
+	if m, ok := AsMap(left); ok {
+		key, ok := right.(string)
+		if !ok {
+			return false, NFError.NewErrTypeMismatch("string", fmt.Sprintf("%T", right))
+		}
+		_, ok = m[key]
+		return ok, nil
+	}
+	val := reflect.ValueOf(left)
+	if val.Kind() == reflect.Slice || val.Kind() == reflect.Array {
+		for i := 0; i < val.Len(); i++ {
+			if equal(val.Index(i).Interface(), right) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return false, NFError.NewErrInvalidArgument("contains", fmt.Sprintf("can not search in %T", left))
+}
+
+// EvaluateCondition evaluates a condition map
+//
+// A condition compares the variable at "Location" and "Key" with "Value" using "Operator" ("==" by default),
+// "Value" can itself be a reference map with a Location and Key. Conditions can be combined with
+// "All" and "Any" lists of conditions, and any condition can be inverted with "Not"
+//
+// A condition can also be an NFExpression, in which case the result of the expression is used
+func EvaluateCondition(condition interface{}) (bool, error) {
+	if expression, ok := AsExpression(condition); ok {
+		result, err := expression.Evaluate()
+		if err != nil {
+			return false, err
+		}
+		return truthy(result), nil
+	}
+	m, ok := AsMap(condition)
+	if !ok {
+		if b, ok := condition.(bool); ok {
+			return b, nil
+		}
+		return false, NFError.NewErrInvalidArgument("Condition", fmt.Sprintf("condition must be a map not %T", condition))
+	}
+	result, err := evaluateConditionMap(m)
+	if err != nil {
+		return false, err
+	}
+	if not, ok := m["Not"].(bool); ok && not {
+		return !result, nil
+	}
+	return result, nil
+}
+
+func evaluateConditionMap(m CustomMap) (bool, error) {
+	if all, ok := m["All"]; ok {
+		conditions, ok := all.([]interface{})
+		if !ok {
+			return false, NFError.NewErrInvalidArgument("All", "must be a list of conditions")
+		}
+		for _, c := range conditions {
+			result, err := EvaluateCondition(c)
+			if err != nil || !result {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	if any, ok := m["Any"]; ok {
+		conditions, ok := any.([]interface{})
+		if !ok {
+			return false, NFError.NewErrInvalidArgument("Any", "must be a list of conditions")
+		}
+		for _, c := range conditions {
+			result, err := EvaluateCondition(c)
+			if err != nil {
+				return false, err
+			}
+			if result {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	ref, ok := AsReference(m)
+	if !ok {
+		return false, NFError.NewErrInvalidArgument("Condition", "condition needs a Location and Key")
+	}
+	op := OpEqual
+	if opString, ok := m["Operator"].(string); ok && opString != "" {
+		op = Operator(opString)
+	}
+	left, err := ref.UnTypedGet()
+	if err != nil {
+		if !errors.Is(err, NFError.ErrKeyNotFound) {
+			return false, err
+		}
+		//Unset variables are nil so they can still be checked with isSet
+		left = nil
+	}
+	right, err := Resolve(m["Value"])
+	if err != nil {
+		return false, err
+	}
+	return Compare(left, op, right)
+}
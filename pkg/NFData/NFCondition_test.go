@@ -0,0 +1,178 @@
+package NFData
+
+import (
+	"errors"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name  string
+		left  interface{}
+		op    Operator
+		right interface{}
+		want  bool
+	}{
+		{"equal ints", 1, OpEqual, 1, true},
+		{"int equals float", 2, OpEqual, 2.0, true},
+		{"float64 equals float32", 0.5, OpEqual, float32(0.5), true},
+		{"equal strings", "a", OpEqual, "a", true},
+		{"string does not equal number", "1", OpEqual, 1, false},
+		{"not equal", 1, OpNotEqual, 2, true},
+		{"nil equals nil", nil, OpEqual, nil, true},
+		{"less", 1, OpLess, 2, true},
+		{"less or equal", 2, OpLessOrEqual, 2.0, true},
+		{"greater", 3.5, OpGreater, 3, true},
+		{"greater or equal", 3, OpGreaterOrEqual, 4, false},
+		{"strings are ordered", "apple", OpLess, "banana", true},
+		{"string contains", "lantern", OpContains, "tern", true},
+		{"string does not contain", "lantern", OpNotContains, "x", true},
+		{"list contains", []interface{}{"key", 2}, OpContains, 2.0, true},
+		{"list does not contain", []string{"key"}, OpContains, "lamp", false},
+		{"map contains key", map[string]interface{}{"key": 1}, OpContains, "key", true},
+		{"is set", 0, OpIsSet, nil, true},
+		{"nil is not set", nil, OpNotSet, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Compare(test.left, test.op, test.right)
+			if err != nil {
+				t.Fatalf("Compare(%v, %s, %v) returned an error: %v", test.left, test.op, test.right, err)
+			}
+			if got != test.want {
+				t.Errorf("Compare(%v, %s, %v) = %v, want %v", test.left, test.op, test.right, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCompareErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		left  interface{}
+		op    Operator
+		right interface{}
+		want  error
+	}{
+		{"ordered bool", true, OpLess, 1, NFError.ErrTypeMismatch},
+		{"ordered string and number", "a", OpGreater, 1, NFError.ErrTypeMismatch},
+		{"string contains number", "a", OpContains, 1, NFError.ErrTypeMismatch},
+		{"map contains number", map[string]interface{}{}, OpContains, 1, NFError.ErrTypeMismatch},
+		{"contains in a number", 1, OpContains, 1, NFError.ErrInvalidArgument},
+		{"unknown operator", 1, Operator("~"), 1, NFError.ErrInvalidArgument},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Compare(test.left, test.op, test.right)
+			if !errors.Is(err, test.want) {
+				t.Errorf("Compare(%v, %s, %v) returned %v, want %v", test.left, test.op, test.right, err, test.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	setExpressionVariables(t)
+	condition := func(location, key, op string, value interface{}) map[string]interface{} {
+		return map[string]interface{}{"Location": location, "Key": key, "Operator": op, "Value": value}
+	}
+	tests := []struct {
+		name      string
+		condition interface{}
+		want      bool
+	}{
+		{"equal by default", map[string]interface{}{"Location": "Global", "Key": "Name", "Value": "Ada"}, true},
+		{"operator", condition("Global", "Count", ">", 40), true},
+		{"scene variable", condition("Scene", "Flag", "==", true), true},
+		{"unset variable is not set", condition("Global", "Missing", "!isSet", nil), true},
+		{"unset variable does not equal a value", condition("Global", "Missing", "==", 1), false},
+		{"reference value", condition("Global", "Count", "<", map[string]interface{}{"Location": "Global", "Key": "Count"}), false},
+		{"expression value", condition("Global", "Count", "==", map[string]interface{}{"Expression": "40 + 1"}), true},
+		{"not", map[string]interface{}{"Location": "Scene", "Key": "Flag", "Value": true, "Not": true}, false},
+		{"all", map[string]interface{}{"All": []interface{}{
+			condition("Global", "Name", "==", "Ada"),
+			condition("Global", "Count", ">=", 41),
+		}}, true},
+		{"all with a false condition", map[string]interface{}{"All": []interface{}{
+			condition("Global", "Name", "==", "Ada"),
+			condition("Global", "Count", ">", 41),
+		}}, false},
+		{"any", map[string]interface{}{"Any": []interface{}{
+			condition("Global", "Name", "==", "Bob"),
+			condition("Global", "Items", "contains", "lamp"),
+		}}, true},
+		{"not any", map[string]interface{}{"Not": true, "Any": []interface{}{
+			condition("Global", "Name", "==", "Bob"),
+		}}, true},
+		{"nested", map[string]interface{}{"All": []interface{}{
+			map[string]interface{}{"Any": []interface{}{false, true}},
+			map[string]interface{}{"Expression": "Scene.Speed > 1"},
+		}}, true},
+		{"custom map", CustomMap{"Location": "Global", "Key": "Count", "Value": 41}, true},
+		{"expression", NewExpression("Global.Count == 41 && Scene.Flag"), true},
+		{"expression result is truthy", map[string]interface{}{"Expression": "Global.Name"}, true},
+		{"bool", false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := EvaluateCondition(test.condition)
+			if err != nil {
+				t.Fatalf("EvaluateCondition(%v) returned an error: %v", test.condition, err)
+			}
+			if got != test.want {
+				t.Errorf("EvaluateCondition(%v) = %v, want %v", test.condition, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateConditionErrors(t *testing.T) {
+	setExpressionVariables(t)
+	tests := []struct {
+		name      string
+		condition interface{}
+		want      error
+	}{
+		{"not a map", "Global.Count", NFError.ErrInvalidArgument},
+		{"no reference", map[string]interface{}{"Value": 1}, NFError.ErrInvalidArgument},
+		{"unknown location", map[string]interface{}{"Location": "Player", "Key": "Name"}, NFError.ErrInvalidArgument},
+		{"all is not a list", map[string]interface{}{"All": true}, NFError.ErrInvalidArgument},
+		{"any is not a list", map[string]interface{}{"Any": "x"}, NFError.ErrInvalidArgument},
+		{"error in all", map[string]interface{}{"All": []interface{}{"x"}}, NFError.ErrInvalidArgument},
+		{"broken expression", NewExpression("1 +"), NFError.ErrExpression},
+		{"broken expression value", map[string]interface{}{"Location": "Global", "Key": "Count", "Value": map[string]interface{}{"Expression": "("}}, NFError.ErrExpression},
+		{"mismatched types", map[string]interface{}{"Location": "Global", "Key": "Name", "Operator": "<", "Value": 1}, NFError.ErrTypeMismatch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := EvaluateCondition(test.condition)
+			if !errors.Is(err, test.want) {
+				t.Errorf("EvaluateCondition(%v) returned %v, want %v", test.condition, err, test.want)
+			}
+		})
+	}
+}
+
+func TestAsReference(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  bool
+	}{
+		{"reference", NewRef(NFRefGlobal, "Name"), true},
+		{"map", map[string]interface{}{"Location": "Save", "Key": "Gold"}, true},
+		{"persistent", map[string]interface{}{"Location": "Persistent", "Key": "Endings"}, true},
+		{"unknown location", map[string]interface{}{"Location": "Player", "Key": "Name"}, false},
+		{"empty key", map[string]interface{}{"Location": "Global", "Key": ""}, false},
+		{"no key", map[string]interface{}{"Location": "Global"}, false},
+		{"string", "Global.Name", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, ok := AsReference(test.value); ok != test.want {
+				t.Errorf("AsReference(%v) = %v, want %v", test.value, ok, test.want)
+			}
+		})
+	}
+}
@@ -27,6 +27,27 @@ type NFConfig struct {
 	Icon string `json:"Icon"`
 	//Encryption key for the project
 	EncryptionKey string `json:"EncryptionKey"`
+	// Autosave is when the game saves on its own, configs without it never autosave
+	Autosave Autosave `json:"Autosave"`
+	// SaveEncoding is how saves are written, "json" or "binary" for compressed saves, configs without it write JSON
+	SaveEncoding string `json:"SaveEncoding,omitempty"`
+}
+
+// Autosave holds the autosave settings of a game
+type Autosave struct {
+	// Slots is how many autosave slots are kept, the oldest is overwritten once they are all used
+	Slots int `json:"Slots"`
+	// OnSceneChange autosaves every time a new scene is shown
+	OnSceneChange bool `json:"OnSceneChange"`
+	// BeforeChoice autosaves when an option of a choice menu is picked, before the choice is recorded
+	BeforeChoice bool `json:"BeforeChoice"`
+	// Interval is the time in seconds between timed autosaves, 0 turns them off
+	Interval float64 `json:"Interval"`
+}
+
+// Enabled returns true if there are autosave slots to save to
+func (a Autosave) Enabled() bool {
+	return a.Slots > 0
 }
 
 // NewConfig creates a new config with the given name, author, version and credits
@@ -36,6 +57,12 @@ func NewConfig(name, version, author, credits string) *NFConfig {
 		Version: version,
 		Author:  author,
 		Credits: credits,
+		Autosave: Autosave{
+			Slots:         3,
+			OnSceneChange: true,
+			BeforeChoice:  true,
+			Interval:      300,
+		},
 	}
 }
 
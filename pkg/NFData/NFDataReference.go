@@ -1,7 +1,10 @@
 package NFData
 
 import (
+	"errors"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+	"reflect"
 )
 
 type Type string
@@ -9,8 +12,16 @@ type Type string
 const (
 	NFRefScene  Type = "Scene"
 	NFRefGlobal Type = "Global"
+	NFRefSave   Type = "Save"
+	// NFRefPersistent points to NFSave.Persistent, the data shared by every save such as unlocks and endings seen
+	NFRefPersistent Type = "Persistent"
 )
 
+// GetRefTypes returns all the locations a reference can point to
+func GetRefTypes() []Type {
+	return []Type{NFRefGlobal, NFRefScene, NFRefSave, NFRefPersistent}
+}
+
 type NFReference struct {
 	Location    Type                 `json:"Location"`
 	Key         string               `json:"Key"`
@@ -36,28 +47,103 @@ func NewRefWithBinding(t Type, key string, bindingType SupportedBindingType) NFR
 	}
 }
 
-// Get gets the value of the reference
-func (r *NFReference) Get(ref interface{}) error {
+// variables returns the NFInterfaceMap that holds the values for Scene and Global references
+func (r *NFReference) variables() (*NFInterfaceMap, error) {
 	switch r.Location {
 	case NFRefScene:
-		return ActiveSceneData.Variables.Get(r.Key, ref)
+		if ActiveSceneData == nil {
+			return nil, NFError.NewErrNotFound("no active scene data for reference: " + r.Key)
+		}
+		return ActiveSceneData.Variables, nil
 	case NFRefGlobal:
-		return GlobalVars.Get(r.Key, ref)
+		return GlobalVars, nil
 	default:
-		return NFError.NewErrInvalidArgument("reference", "type not found")
+		return nil, NFError.NewErrInvalidArgument("reference", "type not found")
 	}
 }
 
-func (r *NFReference) GetBinding() (interface{}, error) {
-	var bindings *NFBindingMap
+// bindings returns the NFBindingMap for the location, Save and Persistent references share the global bindings
+func (r *NFReference) bindings() (*NFBindingMap, error) {
 	switch r.Location {
 	case NFRefScene:
-		bindings = ActiveSceneData.Bindings
-	case NFRefGlobal:
-		bindings = GlobalBindings
+		if ActiveSceneData == nil {
+			return nil, NFError.NewErrNotFound("no active scene data for reference: " + r.Key)
+		}
+		return ActiveSceneData.Bindings, nil
+	case NFRefGlobal, NFRefSave, NFRefPersistent:
+		return GlobalBindings, nil
 	default:
 		return nil, NFError.NewErrInvalidArgument("reference", "type not found")
 	}
+}
+
+// isData returns true if the location is stored in typed save data rather than an NFInterfaceMap
+func (r *NFReference) isData() bool {
+	return r.Location == NFRefSave || r.Location == NFRefPersistent
+}
+
+// data returns the typed data for Save and Persistent references, Save references need an active save
+func (r *NFReference) data() (*NFSave.Data, error) {
+	if r.Location == NFRefPersistent {
+		return &NFSave.Persistent.Data, nil
+	}
+	if NFSave.Active == nil {
+		return nil, NFError.NewErrNotFound("no active save")
+	}
+	return &NFSave.Active.Data, nil
+}
+
+// Get gets the value of the reference
+func (r *NFReference) Get(ref interface{}) error {
+	if r.isData() {
+		value, err := r.UnTypedGet()
+		if err != nil {
+			return err
+		}
+		refValue := reflect.ValueOf(ref)
+		if refValue.Kind() != reflect.Ptr {
+			return errors.New("ref must be a pointer")
+		}
+		if !reflect.TypeOf(value).AssignableTo(refValue.Elem().Type()) {
+			return NFError.NewErrTypeMismatch(reflect.TypeOf(value).String(), refValue.Elem().Type().String())
+		}
+		refValue.Elem().Set(reflect.ValueOf(value))
+		return nil
+	}
+	variables, err := r.variables()
+	if err != nil {
+		return err
+	}
+	return variables.Get(r.Key, ref)
+}
+
+// UnTypedGet gets the value of the reference without needing to know its type
+func (r *NFReference) UnTypedGet() (interface{}, error) {
+	if r.isData() {
+		data, err := r.data()
+		if err != nil {
+			return nil, err
+		}
+		if value, ok := data.GetValue(r.Key); ok {
+			return value, nil
+		}
+		return nil, NFError.NewErrKeyNotFound(r.Key)
+	}
+	variables, err := r.variables()
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := variables.UnTypedGet(r.Key); ok {
+		return value, nil
+	}
+	return nil, NFError.NewErrKeyNotFound(r.Key)
+}
+
+func (r *NFReference) GetBinding() (interface{}, error) {
+	bindings, err := r.bindings()
+	if err != nil {
+		return nil, err
+	}
 	switch r.BindingType {
 	case BindingUntyped:
 		return bindings.GetUntypedBinding(r.Key)
@@ -76,50 +162,89 @@ func (r *NFReference) GetBinding() (interface{}, error) {
 
 // Add adds the reference to the Location
 func (r *NFReference) Add(ref interface{}) error {
-	switch r.Location {
-	case NFRefScene:
-		return ActiveSceneData.Variables.Add(r.Key, ref)
-	case NFRefGlobal:
-		return GlobalVars.Add(r.Key, ref)
-	default:
-		return NFError.NewErrInvalidArgument("reference", "type not found")
+	if r.isData() {
+		data, err := r.data()
+		if err != nil {
+			return err
+		}
+		if _, ok := data.GetValue(r.Key); ok {
+			return NFError.NewErrKeyAlreadyExists(r.Key)
+		}
+		err = data.SetValue(r.Key, ref)
+		if err != nil {
+			return err
+		}
+		return r.updateBinding(ref)
+	}
+	variables, err := r.variables()
+	if err != nil {
+		return err
+	}
+	err = variables.Add(r.Key, ref)
+	if err != nil {
+		return err
 	}
+	return r.updateBinding(ref)
 }
 
 // CreateBinding creates a new binding for the reference
 func (r *NFReference) CreateBinding(ref interface{}) error {
-	switch r.Location {
-	case NFRefScene:
-		return ActiveSceneData.Bindings.CreateBinding(r.Key, ref)
-	case NFRefGlobal:
-		return GlobalBindings.CreateBinding(r.Key, ref)
-	default:
-		return NFError.NewErrInvalidArgument("reference", "type not found")
+	bindings, err := r.bindings()
+	if err != nil {
+		return err
 	}
+	return bindings.CreateBinding(r.Key, ref)
 }
 
-// Delete deletes the reference from the Location
+// Delete deletes the reference from the Location, any bindings with the same key are reset to their zero value
 func (r *NFReference) Delete() error {
-	switch r.Location {
-	case NFRefScene:
-		return ActiveSceneData.Variables.Delete(r.Key)
-	case NFRefGlobal:
-		return GlobalVars.Delete(r.Key)
-	default:
-		return NFError.NewErrInvalidArgument("reference", "type not found")
+	if r.isData() {
+		data, err := r.data()
+		if err != nil {
+			return err
+		}
+		if !data.DeleteValue(r.Key) {
+			return NFError.NewErrKeyNotFound(r.Key)
+		}
+		return r.updateBinding(nil)
+	}
+	variables, err := r.variables()
+	if err != nil {
+		return err
 	}
+	err = variables.Delete(r.Key)
+	if err != nil {
+		return err
+	}
+	return r.updateBinding(nil)
 }
 
-// Set sets the value of the reference
+// Set sets the value of the reference, any bindings with the same key are updated to the new value
 func (r *NFReference) Set(ref interface{}) error {
-	switch r.Location {
-	case NFRefScene:
-		ActiveSceneData.Variables.Set(r.Key, ref)
-		return nil
-	case NFRefGlobal:
-		GlobalVars.Set(r.Key, ref)
-		return nil
-	default:
-		return NFError.NewErrInvalidArgument("reference", "type not found")
+	if r.isData() {
+		data, err := r.data()
+		if err != nil {
+			return err
+		}
+		err = data.SetValue(r.Key, ref)
+		if err != nil {
+			return err
+		}
+		return r.updateBinding(ref)
+	}
+	variables, err := r.variables()
+	if err != nil {
+		return err
+	}
+	variables.Set(r.Key, ref)
+	return r.updateBinding(ref)
+}
+
+// updateBinding updates any existing binding with the same key as the reference so bound widgets refresh
+func (r *NFReference) updateBinding(value interface{}) error {
+	bindings, err := r.bindings()
+	if err != nil {
+		return err
 	}
+	return bindings.UpdateBinding(r.Key, value)
 }
@@ -1,40 +1,182 @@
 package NFEncryption
 
 import (
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"encoding/binary"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"golang.org/x/crypto/scrypt"
 	"io"
+	"strconv"
 )
 
+// Magic is the start of every file encrypted in the current format, files without it are decrypted with the legacy format
+var Magic = []byte("NFEC")
+
+// FormatVersion is the version of the header written in front of encrypted data
+const FormatVersion = 1
+
+// AlgorithmAESGCM encrypts the data in chunks of ChunkSize with AES-256-GCM
+const AlgorithmAESGCM = 1
+
+// KDFScrypt derives the key from the password with scrypt
+const KDFScrypt = 1
+
+// ScryptLogN, ScryptR and ScryptP are the scrypt cost used for new data,
+// the cost is kept in the header so data encrypted with an older cost still decrypts if they are changed
+var (
+	ScryptLogN byte = 15
+	ScryptR    byte = 8
+	ScryptP    byte = 1
+)
+
+// maxScryptLogN is the highest cost a header may ask for, so a damaged header can not use up all the memory
+const maxScryptLogN = 22
+
+// ChunkSize is how much plaintext is in each authenticated chunk
+const ChunkSize = 64 * 1024
+
+const saltSize = 16
+const noncePrefixSize = 7
+
+// HeaderSize is the size of the header written in front of encrypted data
+const HeaderSize = 4 + 6 + saltSize + noncePrefixSize
+
+// header is the unencrypted start of encrypted data, it is authenticated with every chunk so it can not be changed
+//
+// The layout is the Magic, the FormatVersion, the algorithm, the key derivation function, the scrypt log N, r and p,
+// the salt and the prefix of the chunk nonces
+type header struct {
+	version     byte
+	algorithm   byte
+	kdf         byte
+	logN, r, p  byte
+	salt        [saltSize]byte
+	noncePrefix [noncePrefixSize]byte
+}
+
+// newHeader creates a header with a random salt and nonce prefix using the current format
+func newHeader() (header, error) {
+	h := header{version: FormatVersion, algorithm: AlgorithmAESGCM, kdf: KDFScrypt, logN: ScryptLogN, r: ScryptR, p: ScryptP}
+	if _, err := io.ReadFull(rand.Reader, h.salt[:]); err != nil {
+		return header{}, err
+	}
+	if _, err := io.ReadFull(rand.Reader, h.noncePrefix[:]); err != nil {
+		return header{}, err
+	}
+	return h, nil
+}
+
+// bytes returns the header as it is written in front of the data
+func (h header) bytes() []byte {
+	b := make([]byte, 0, HeaderSize)
+	b = append(b, Magic...)
+	b = append(b, h.version, h.algorithm, h.kdf, h.logN, h.r, h.p)
+	b = append(b, h.salt[:]...)
+	return append(b, h.noncePrefix[:]...)
+}
+
+// readHeader reads and checks the header at the start of src
+func readHeader(src io.Reader) (header, error) {
+	b := make([]byte, HeaderSize)
+	if _, err := io.ReadFull(src, b); err != nil {
+		return header{}, NFError.NewErrDecryption("the header is incomplete")
+	}
+	if !bytes.Equal(b[:len(Magic)], Magic) {
+		return header{}, NFError.NewErrDecryption("the data does not start with the encryption header")
+	}
+	b = b[len(Magic):]
+	h := header{version: b[0], algorithm: b[1], kdf: b[2], logN: b[3], r: b[4], p: b[5]}
+	copy(h.salt[:], b[6:6+saltSize])
+	copy(h.noncePrefix[:], b[6+saltSize:])
+	switch {
+	case h.version != FormatVersion:
+		return header{}, NFError.NewErrDecryption("unsupported format version " + strconv.Itoa(int(h.version)))
+	case h.algorithm != AlgorithmAESGCM:
+		return header{}, NFError.NewErrDecryption("unsupported algorithm " + strconv.Itoa(int(h.algorithm)))
+	case h.kdf != KDFScrypt:
+		return header{}, NFError.NewErrDecryption("unsupported key derivation function " + strconv.Itoa(int(h.kdf)))
+	case h.logN == 0 || h.logN > maxScryptLogN || h.r == 0 || h.p == 0:
+		return header{}, NFError.NewErrDecryption("invalid scrypt cost")
+	}
+	return h, nil
+}
+
+// aead derives the key from the password with the header's salt and cost and creates the cipher for the chunks
+func (h header) aead(password string) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(password), h.salt[:], 1<<h.logN, int(h.r), int(h.p), 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the nonce of a chunk, which is the header's prefix, the chunk's number and whether it is the last chunk
+//
+// Marking the last chunk means data that was cut short at a chunk boundary fails to decrypt
+func (h header) chunkNonce(counter uint32, last bool) []byte {
+	nonce := make([]byte, 0, noncePrefixSize+5)
+	nonce = append(nonce, h.noncePrefix[:]...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
 // Encrypt takes a plaintext byte array and encrypts it using the provided key.
 // It returns the ciphertext and an error if one occurs.
-// It generates the cipher text using AES-GCM and a nonce.
-// A Nonce is a number that can only be used once and will be unique for each encryption, but it is not a secret
-// It is safe to store the nonce alongside the ciphertext
-// The nonce is prepended to the ciphertext
+//
+// The key is derived from the password with scrypt and a random salt, and the plaintext is encrypted with AES-256-GCM.
+// The salt, the scrypt cost and the nonce are kept in a header in front of the ciphertext, they are not secret.
+// This is the same format NewWriter writes, so data from either can be decrypted with Decrypt or NewReader
 func Encrypt(plaintext []byte, key string) ([]byte, error) {
-	block, err := aes.NewCipher(validateKey([]byte(key), 32))
+	var ciphertext bytes.Buffer
+	ciphertext.Grow(HeaderSize + len(plaintext) + (len(plaintext)/ChunkSize+1)*16)
+	w, err := NewWriter(&ciphertext, key)
 	if err != nil {
 		return nil, err
 	}
-
-	nonce := make([]byte, 12)
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+	if _, err = w.Write(plaintext); err != nil {
 		return nil, err
 	}
-
-	newGCM, err := cipher.NewGCM(block)
-	if err != nil {
+	if err = w.Close(); err != nil {
 		return nil, err
 	}
-
-	ciphertext := newGCM.Seal(nonce, nonce, plaintext, nil)
-	return ciphertext, nil
+	return ciphertext.Bytes(), nil
 }
 
 // Decrypt takes a ciphertext byte array and decrypts it using the provided key
+//
+// Ciphertext from before the header was added is decrypted with the legacy format
 func Decrypt(ciphertext []byte, key string) ([]byte, error) {
+	if !bytes.HasPrefix(ciphertext, Magic) {
+		return decryptLegacy(ciphertext, key)
+	}
+	r, err := NewReader(bytes.NewReader(ciphertext), key)
+	if err == nil {
+		var plaintext []byte
+		plaintext, err = io.ReadAll(r)
+		if err == nil {
+			return plaintext, nil
+		}
+	}
+	//Legacy ciphertext starts with a random nonce, which can start with the magic by chance
+	if plaintext, legacyErr := decryptLegacy(ciphertext, key); legacyErr == nil {
+		return plaintext, nil
+	}
+	return nil, err
+}
+
+// decryptLegacy decrypts ciphertext from before the header was added, which is the nonce followed by the AES-GCM ciphertext
+// of the plaintext with the key padded or cut to 32 bytes
+func decryptLegacy(ciphertext []byte, key string) ([]byte, error) {
 	block, err := aes.NewCipher(validateKey([]byte(key), 32))
 	if err != nil {
 		return nil, err
@@ -47,7 +189,7 @@ func Decrypt(ciphertext []byte, key string) ([]byte, error) {
 
 	nonceSize := newGCM.NonceSize()
 	if len(ciphertext) < nonceSize {
-		return nil, err
+		return nil, NFError.NewErrDecryption("the ciphertext is shorter than the nonce")
 	}
 
 	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
@@ -60,6 +202,8 @@ func Decrypt(ciphertext []byte, key string) ([]byte, error) {
 }
 
 // validateKey takes a key and a length and returns a key of the correct length by truncating or padding it
+//
+// It is only used to decrypt the legacy format
 func validateKey(key []byte, length int) []byte {
 	if len(key) == length {
 		return key
@@ -0,0 +1,199 @@
+package NFEncryption
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"errors"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"io"
+	"math"
+)
+
+// writer encrypts what is written to it in chunks of ChunkSize, see NewWriter
+type writer struct {
+	dst     io.Writer
+	header  header
+	aad     []byte
+	aead    cipher.AEAD
+	counter uint32
+	buf     []byte
+	closed  bool
+}
+
+// NewWriter returns a writer that encrypts everything written to it in to dst using the provided key
+//
+// The header is written straight away and each chunk is written once it is full,
+// Close must be called to write the last chunk, without it the data can not be decrypted
+func NewWriter(dst io.Writer, key string) (io.WriteCloser, error) {
+	h, err := newHeader()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := h.aead(key)
+	if err != nil {
+		return nil, err
+	}
+	aad := h.bytes()
+	if _, err = dst.Write(aad); err != nil {
+		return nil, err
+	}
+	return &writer{dst: dst, header: h, aad: aad, aead: aead, buf: make([]byte, 0, ChunkSize)}, nil
+}
+
+// seal encrypts the plaintext as the next chunk and writes it
+func (w *writer) seal(plaintext []byte, last bool) error {
+	if w.counter == math.MaxUint32 {
+		return NFError.NewErrInvalidArgument("plaintext", "too much data for one stream")
+	}
+	chunk := w.aead.Seal(nil, w.header.chunkNonce(w.counter, last), plaintext, w.aad)
+	w.counter++
+	_, err := w.dst.Write(chunk)
+	return err
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("write to closed encryption writer")
+	}
+	written := 0
+	for len(p) > 0 {
+		//A full chunk is only sealed once more data arrives, so the last chunk is never empty unless there is no data at all
+		if len(w.buf) == ChunkSize {
+			if err := w.seal(w.buf, false); err != nil {
+				return written, err
+			}
+			w.buf = w.buf[:0]
+		}
+		n := copy(w.buf[len(w.buf):ChunkSize], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close writes the last chunk, it does not close dst
+func (w *writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.seal(w.buf, true)
+}
+
+// reader decrypts chunks written by a writer, see NewReader
+type reader struct {
+	src       *bufio.Reader
+	header    header
+	aad       []byte
+	aead      cipher.AEAD
+	counter   uint32
+	chunk     []byte
+	buf       []byte
+	plaintext []byte
+	done      bool
+}
+
+// NewReader returns a reader that decrypts the data in src using the provided key
+//
+// Each chunk is checked before any of it is returned, so changed or cut short data fails with NFError.ErrDecryption
+// instead of returning wrong plaintext. Data in the legacy format is read and decrypted in full before the first read
+func NewReader(src io.Reader, key string) (io.Reader, error) {
+	buffered := bufio.NewReaderSize(src, ChunkSize)
+	start, err := buffered.Peek(len(Magic))
+	if err != nil || !bytes.Equal(start, Magic) {
+		ciphertext, err := io.ReadAll(buffered)
+		if err != nil {
+			return nil, err
+		}
+		plaintext, err := decryptLegacy(ciphertext, key)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(plaintext), nil
+	}
+	h, err := readHeader(buffered)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := h.aead(key)
+	if err != nil {
+		return nil, err
+	}
+	return &reader{
+		src:    buffered,
+		header: h,
+		aad:    h.bytes(),
+		aead:   aead,
+		chunk:  make([]byte, ChunkSize+aead.Overhead()),
+		buf:    make([]byte, 0, ChunkSize),
+	}, nil
+}
+
+// next reads and decrypts the next chunk
+func (r *reader) next() error {
+	n, err := io.ReadFull(r.src, r.chunk)
+	last := false
+	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return err
+	default:
+		//A full chunk is the last one if nothing follows it
+		if _, peekErr := r.src.Peek(1); errors.Is(peekErr, io.EOF) {
+			last = true
+		}
+	}
+	if n < r.aead.Overhead() {
+		return NFError.NewErrDecryption("the data was cut short")
+	}
+	plaintext, err := r.aead.Open(r.buf[:0], r.header.chunkNonce(r.counter, last), r.chunk[:n], r.aad)
+	if err != nil {
+		return NFError.NewErrDecryption("the key is wrong or the data was changed")
+	}
+	r.counter++
+	r.plaintext = plaintext
+	r.done = last
+	return nil
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	for len(r.plaintext) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.plaintext)
+	r.plaintext = r.plaintext[n:]
+	return n, nil
+}
+
+// EncryptStream encrypts everything read from src in to dst using the provided key, it is meant for files too large to
+// hold in memory such as assets
+func EncryptStream(dst io.Writer, src io.Reader, key string) error {
+	w, err := NewWriter(dst, key)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(w, src); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// DecryptStream decrypts everything read from src in to dst using the provided key
+//
+// Chunks are written to dst as they are checked, so if an error is returned dst may already hold the start of the plaintext
+func DecryptStream(dst io.Writer, src io.Reader, key string) error {
+	r, err := NewReader(src, key)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, r)
+	return err
+}
@@ -24,6 +24,15 @@ var (
 	ErrCriticalSceneValidation = errors.New("critical scene validation failure")
 	ErrNotFound                = errors.New("not found")
 	ErrWidgetParse             = errors.New("error parsing widget")
+	ErrExpression              = errors.New("error evaluating expression")
+	ErrScriptParse             = errors.New("error parsing script")
+	ErrSaveTooNew              = errors.New("save is from a newer version of the game")
+	ErrSaveMigration           = errors.New("error migrating save")
+	ErrDecryption              = errors.New("error decrypting data")
+	ErrSaveCorrupt             = errors.New("save is corrupt")
+	ErrSaveRecovered           = errors.New("save was recovered")
+	ErrBrokenReference         = errors.New("broken reference")
+	ErrMissingStateProvider    = errors.New("missing state provider")
 )
 
 func NewErrInvalidArgument(arg, reason string) error {
@@ -89,3 +98,46 @@ func NewErrNotFound(s string) error {
 func NewErrWidgetParse(widgetName, widgetType string, widgetUUID uuid.UUID, reason string) error {
 	return fmt.Errorf("%w: %s of type %s with UUID %v: %s", ErrWidgetParse, widgetName, widgetType, widgetUUID, reason)
 }
+
+func NewErrExpression(expression, reason string) error {
+	return fmt.Errorf("%w: %q: %s", ErrExpression, expression, reason)
+}
+
+func NewErrScriptParse(file string, line int, reason string) error {
+	return fmt.Errorf("%w: %s:%d: %s", ErrScriptParse, file, line, reason)
+}
+
+func NewErrSaveTooNew(saveVersion, currentVersion int) error {
+	return fmt.Errorf("%w: the save has schema version %d but the game only supports up to %d", ErrSaveTooNew, saveVersion, currentVersion)
+}
+
+func NewErrSaveMigration(fromVersion int, reason string) error {
+	return fmt.Errorf("%w: from schema version %d: %s", ErrSaveMigration, fromVersion, reason)
+}
+
+func NewErrDecryption(reason string) error {
+	return fmt.Errorf("%w: %s", ErrDecryption, reason)
+}
+
+func NewErrSaveCorrupt(reason string) error {
+	return fmt.Errorf("%w: %s", ErrSaveCorrupt, reason)
+}
+
+// NewErrSaveRecovered describes a save that could not be loaded and was replaced by one of its snapshots,
+// the error that stopped the save from loading is wrapped as well
+func NewErrSaveRecovered(save string, snapshot int, cause error) error {
+	return fmt.Errorf("%w: %s was restored from snapshot %d because it could not be loaded: %w", ErrSaveRecovered, save, snapshot, cause)
+}
+
+// NewErrBrokenReference describes a reference by name to something that does not exist, such as a prefab or base scene.
+// It is not an ErrSceneValidation, the reference may only be missing because it is not registered yet,
+// so scenes that have one are reported by Validate without being changed and saved again when they load
+func NewErrBrokenReference(kind, name string) error {
+	return fmt.Errorf("%w: %s %q does not exist", ErrBrokenReference, kind, name)
+}
+
+// NewErrMissingStateProvider describes a section of a save that no registered state provider restores,
+// usually because the package that registers the provider is not imported by the game
+func NewErrMissingStateProvider(section string) error {
+	return fmt.Errorf("%w: no provider is registered for the %q section of the save", ErrMissingStateProvider, section)
+}
@@ -0,0 +1,600 @@
+package NFData
+
+import (
+	"fmt"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// NFExpression is a small sandboxed expression that can be stored in any Arg instead of a literal value,
+// it is evaluated when the Arg is read with NFInterfaceMap.Get.
+//
+// Expressions support
+//   - literals: 1, 2.5, true, false, nil, "text" and 'text'
+//   - lookups: Global.Key, Scene.Key and Save.Key, variables that are not set are nil
+//   - arithmetic: + - * / %, + also joins strings
+//   - comparisons: == != < <= > >= contains
+//   - boolean logic: && || ! (or and, or, not)
+//   - interpolation inside string literals: 'Hello {Global.PlayerName}'
+//
+// Expressions can not call functions or change any variables.
+// In json they are written as a map with a single "Expression" key
+type NFExpression struct {
+	Expression string `json:"Expression"`
+}
+
+// MaxExpressionLength is the longest expression that will be evaluated
+const MaxExpressionLength = 4096
+
+// maxExpressionDepth limits how deeply expressions and interpolations can nest
+const maxExpressionDepth = 64
+
+// NewExpression creates a new NFExpression
+func NewExpression(expression string) NFExpression {
+	return NFExpression{Expression: expression}
+}
+
+// AsExpression returns the value as an NFExpression if it is one, or if it is a map with only an "Expression" string
+func AsExpression(value interface{}) (NFExpression, bool) {
+	switch v := value.(type) {
+	case NFExpression:
+		return v, true
+	case *NFExpression:
+		if v == nil {
+			return NFExpression{}, false
+		}
+		return *v, true
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		var custom CustomMap
+		custom, ok = value.(CustomMap)
+		m = custom
+	}
+	if !ok || len(m) != 1 {
+		return NFExpression{}, false
+	}
+	expression, ok := m["Expression"].(string)
+	if !ok {
+		return NFExpression{}, false
+	}
+	return NFExpression{Expression: expression}, true
+}
+
+// Evaluate evaluates the value if it is an expression, otherwise the value is returned unchanged
+func Evaluate(value interface{}) (interface{}, error) {
+	if expression, ok := AsExpression(value); ok {
+		return expression.Evaluate()
+	}
+	return value, nil
+}
+
+// Evaluate parses and evaluates the expression
+func (e NFExpression) Evaluate() (interface{}, error) {
+	return evaluateExpression(e.Expression, 0)
+}
+
+// Check parses the expression without evaluating it, so syntax errors can be reported before the expression is used
+func (e NFExpression) Check() error {
+	if len(e.Expression) > MaxExpressionLength {
+		return NFError.NewErrExpression(e.Expression[:32]+"...", "expression is too long")
+	}
+	tokens, err := lexExpression(e.Expression)
+	if err != nil {
+		return err
+	}
+	p := &expressionParser{expression: e.Expression, tokens: tokens}
+	_, err = p.parse(0)
+	if err != nil {
+		return err
+	}
+	if p.peek().kind != tokenEnd {
+		return p.errorf("unexpected %q", p.peek().text)
+	}
+	return nil
+}
+
+// EvaluateInto evaluates the expression and stores the result in ref, which must be a pointer.
+// Numbers, bools and strings are converted to the type ref points to when needed
+func (e NFExpression) EvaluateInto(ref interface{}) error {
+	refValue := reflect.ValueOf(ref)
+	if refValue.Kind() != reflect.Ptr || refValue.IsNil() {
+		return NFError.NewErrInvalidArgument("ref", "ref must be a pointer")
+	}
+	result, err := e.Evaluate()
+	if err != nil {
+		return err
+	}
+	target := refValue.Elem()
+	if result == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	resultValue := reflect.ValueOf(result)
+	if resultValue.Type().AssignableTo(target.Type()) {
+		target.Set(resultValue)
+		return nil
+	}
+	var valueType ValueType
+	switch target.Kind() {
+	case reflect.Int:
+		valueType = IntType
+	case reflect.Float64:
+		valueType = FloatType
+	case reflect.Bool:
+		valueType = BooleanType
+	case reflect.String:
+		valueType = StringType
+	default:
+		return NFError.NewErrTypeMismatch(target.Type().String(), resultValue.Type().String())
+	}
+	converted, err := ConvertValue(result, valueType)
+	if err != nil {
+		return err
+	}
+	target.Set(reflect.ValueOf(converted).Convert(target.Type()))
+	return nil
+}
+
+// Interpolate replaces every {expression} in the text with its result, use \{ for a literal brace
+func Interpolate(text string) (string, error) {
+	return interpolate(text, text, 0)
+}
+
+func evaluateExpression(expression string, depth int) (interface{}, error) {
+	if len(expression) > MaxExpressionLength {
+		return nil, NFError.NewErrExpression(expression[:32]+"...", "expression is too long")
+	}
+	if depth > maxExpressionDepth {
+		return nil, NFError.NewErrExpression(expression, "expression is nested too deeply")
+	}
+	tokens, err := lexExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &expressionParser{expression: expression, tokens: tokens, depth: depth}
+	node, err := p.parse(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEnd {
+		return nil, p.errorf("unexpected %q", p.peek().text)
+	}
+	return node.eval()
+}
+
+func interpolate(expression, text string, depth int) (string, error) {
+	var builder strings.Builder
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			if i+1 < len(text) && (text[i+1] == '{' || text[i+1] == '}') {
+				builder.WriteByte(text[i+1])
+				i++
+				continue
+			}
+			builder.WriteByte(text[i])
+		case '{':
+			end, nested := i+1, 1
+			for ; end < len(text) && nested > 0; end++ {
+				switch text[end] {
+				case '{':
+					nested++
+				case '}':
+					nested--
+				}
+			}
+			if nested > 0 {
+				return "", NFError.NewErrExpression(expression, "unclosed { in string")
+			}
+			result, err := evaluateExpression(text[i+1:end-1], depth+1)
+			if err != nil {
+				return "", err
+			}
+			if result != nil {
+				builder.WriteString(fmt.Sprint(result))
+			}
+			i = end - 1
+		default:
+			builder.WriteByte(text[i])
+		}
+	}
+	return builder.String(), nil
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type expressionToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// lexExpression splits an expression in to tokens, string tokens hold the raw text between the quotes
+func lexExpression(expression string) ([]expressionToken, error) {
+	tokens := make([]expressionToken, 0)
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, expressionToken{tokenNumber, string(runes[start:i]), start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, expressionToken{tokenIdent, string(runes[start:i]), start})
+		case r == '"' || r == '\'':
+			start := i
+			i++
+			var builder strings.Builder
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					switch runes[i] {
+					case 'n':
+						builder.WriteRune('\n')
+					case 't':
+						builder.WriteRune('\t')
+					case '{', '}':
+						//Kept escaped so interpolation treats them as literal braces
+						builder.WriteRune('\\')
+						builder.WriteRune(runes[i])
+					default:
+						builder.WriteRune(runes[i])
+					}
+					continue
+				}
+				builder.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, NFError.NewErrExpression(expression, "unclosed string starting at "+strconv.Itoa(start))
+			}
+			i++
+			tokens = append(tokens, expressionToken{tokenString, builder.String(), start})
+		default:
+			start := i
+			if i+1 < len(runes) {
+				two := string(runes[i : i+2])
+				if slices.Contains([]string{"==", "!=", "<=", ">=", "&&", "||"}, two) {
+					tokens = append(tokens, expressionToken{tokenOperator, two, start})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("+-*/%<>!()", r) {
+				return nil, NFError.NewErrExpression(expression, fmt.Sprintf("unexpected character %q at %d", r, start))
+			}
+			tokens = append(tokens, expressionToken{tokenOperator, string(r), start})
+			i++
+		}
+	}
+	return append(tokens, expressionToken{kind: tokenEnd, pos: len(runes)}), nil
+}
+
+// binaryPrecedence maps the binary operators to their precedence, higher binds tighter
+var binaryPrecedence = map[string]int{
+	"||": 1, "or": 1,
+	"&&": 2, "and": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4, "contains": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+// unaryPrecedence is the precedence of ! and unary -
+const unaryPrecedence = 7
+
+type expressionParser struct {
+	expression string
+	tokens     []expressionToken
+	current    int
+	depth      int
+}
+
+func (p *expressionParser) peek() expressionToken {
+	return p.tokens[p.current]
+}
+
+func (p *expressionParser) next() expressionToken {
+	t := p.tokens[p.current]
+	if t.kind != tokenEnd {
+		p.current++
+	}
+	return t
+}
+
+func (p *expressionParser) errorf(format string, args ...interface{}) error {
+	return NFError.NewErrExpression(p.expression, fmt.Sprintf(format, args...)+" at "+strconv.Itoa(p.peek().pos))
+}
+
+// operator returns the binary operator at the current token or "" if there is none
+func (p *expressionParser) operator() string {
+	t := p.peek()
+	if t.kind != tokenOperator && t.kind != tokenIdent {
+		return ""
+	}
+	if _, ok := binaryPrecedence[t.text]; ok {
+		return t.text
+	}
+	return ""
+}
+
+// parse parses an expression using precedence climbing, only operators that bind tighter than minPrecedence are consumed
+func (p *expressionParser) parse(minPrecedence int) (expressionNode, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxExpressionDepth {
+		return nil, p.errorf("expression is nested too deeply")
+	}
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.operator()
+		if op == "" || binaryPrecedence[op] <= minPrecedence {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parse(binaryPrecedence[op])
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{expression: p.expression, op: op, left: left, right: right}
+	}
+}
+
+func (p *expressionParser) parseUnary() (expressionNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		if !strings.Contains(t.text, ".") {
+			if i, err := strconv.Atoi(t.text); err == nil {
+				return literalNode{value: i}, nil
+			}
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, NFError.NewErrExpression(p.expression, "invalid number "+t.text)
+		}
+		return literalNode{value: f}, nil
+	case tokenString:
+		if !strings.Contains(t.text, "{") {
+			return literalNode{value: strings.NewReplacer(`\{`, "{", `\}`, "}").Replace(t.text)}, nil
+		}
+		return templateNode{expression: p.expression, text: t.text, depth: p.depth}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "nil":
+			return literalNode{value: nil}, nil
+		case "not":
+			return p.unary("!")
+		}
+		location, key, found := strings.Cut(t.text, ".")
+		if !found || key == "" || !slices.Contains(GetRefTypes(), Type(location)) {
+			return nil, NFError.NewErrExpression(p.expression, "unknown name "+t.text+", lookups must start with one of Global., Scene. or Save.")
+		}
+		return lookupNode{ref: NewRef(Type(location), key)}, nil
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			node, err := p.parse(0)
+			if err != nil {
+				return nil, err
+			}
+			if p.next().text != ")" {
+				return nil, p.errorf("missing )")
+			}
+			return node, nil
+		case "!", "-":
+			return p.unary(t.text)
+		}
+	case tokenEnd:
+		return nil, p.errorf("unexpected end of expression")
+	}
+	return nil, NFError.NewErrExpression(p.expression, fmt.Sprintf("unexpected %q at %d", t.text, t.pos))
+}
+
+func (p *expressionParser) unary(op string) (expressionNode, error) {
+	operand, err := p.parse(unaryPrecedence)
+	if err != nil {
+		return nil, err
+	}
+	return unaryNode{expression: p.expression, op: op, operand: operand}, nil
+}
+
+type expressionNode interface {
+	eval() (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n literalNode) eval() (interface{}, error) {
+	return n.value, nil
+}
+
+type templateNode struct {
+	expression string
+	text       string
+	depth      int
+}
+
+func (n templateNode) eval() (interface{}, error) {
+	return interpolate(n.expression, n.text, n.depth)
+}
+
+type lookupNode struct {
+	ref NFReference
+}
+
+func (n lookupNode) eval() (interface{}, error) {
+	value, err := n.ref.UnTypedGet()
+	if err != nil {
+		//Variables that are not set are nil so they can be checked with == nil
+		return nil, nil
+	}
+	return value, nil
+}
+
+type unaryNode struct {
+	expression string
+	op         string
+	operand    expressionNode
+}
+
+func (n unaryNode) eval() (interface{}, error) {
+	value, err := n.operand.eval()
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		return !truthy(value), nil
+	}
+	if i, ok := value.(int); ok {
+		return -i, nil
+	}
+	if f, ok := ToFloat(value); ok {
+		return -f, nil
+	}
+	return nil, NFError.NewErrExpression(n.expression, fmt.Sprintf("can not negate %T", value))
+}
+
+type binaryNode struct {
+	expression  string
+	op          string
+	left, right expressionNode
+}
+
+func (n binaryNode) eval() (interface{}, error) {
+	left, err := n.left.eval()
+	if err != nil {
+		return nil, err
+	}
+	//&& and || short circuit so the right side is only evaluated when needed
+	switch n.op {
+	case "&&", "and":
+		if !truthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval()
+		return truthy(right), err
+	case "||", "or":
+		if truthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval()
+		return truthy(right), err
+	}
+	right, err := n.right.eval()
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==", "!=", "<", "<=", ">", ">=", "contains":
+		result, err := Compare(left, Operator(n.op), right)
+		if err != nil {
+			return nil, NFError.NewErrExpression(n.expression, err.Error())
+		}
+		return result, nil
+	case "+":
+		leftString, leftIsString := left.(string)
+		rightString, rightIsString := right.(string)
+		if leftIsString || rightIsString {
+			if !leftIsString {
+				leftString = fmt.Sprint(left)
+			}
+			if !rightIsString {
+				rightString = fmt.Sprint(right)
+			}
+			return leftString + rightString, nil
+		}
+	}
+	return n.arithmetic(left, right)
+}
+
+// arithmetic applies + - * / % to two numbers, ints stay ints except for division which always returns a float
+func (n binaryNode) arithmetic(left, right interface{}) (interface{}, error) {
+	leftInt, leftIsInt := left.(int)
+	rightInt, rightIsInt := right.(int)
+	if leftIsInt && rightIsInt && n.op != "/" {
+		switch n.op {
+		case "+":
+			return leftInt + rightInt, nil
+		case "-":
+			return leftInt - rightInt, nil
+		case "*":
+			return leftInt * rightInt, nil
+		case "%":
+			if rightInt == 0 {
+				return nil, NFError.NewErrExpression(n.expression, "division by zero")
+			}
+			return leftInt % rightInt, nil
+		}
+	}
+	leftFloat, leftOk := ToFloat(left)
+	rightFloat, rightOk := ToFloat(right)
+	if !leftOk || !rightOk {
+		return nil, NFError.NewErrExpression(n.expression, fmt.Sprintf("can not use %s on %T and %T", n.op, left, right))
+	}
+	switch n.op {
+	case "+":
+		return leftFloat + rightFloat, nil
+	case "-":
+		return leftFloat - rightFloat, nil
+	case "*":
+		return leftFloat * rightFloat, nil
+	case "/":
+		if rightFloat == 0 {
+			return nil, NFError.NewErrExpression(n.expression, "division by zero")
+		}
+		return leftFloat / rightFloat, nil
+	default:
+		if rightFloat == 0 {
+			return nil, NFError.NewErrExpression(n.expression, "division by zero")
+		}
+		return math.Mod(leftFloat, rightFloat), nil
+	}
+}
+
+// truthy returns whether a value counts as true in boolean logic, nil, false, 0 and "" are false
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	}
+	if f, ok := ToFloat(value); ok {
+		return f != 0
+	}
+	return true
+}
@@ -0,0 +1,219 @@
+package NFData
+
+import (
+	"errors"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// setExpressionVariables sets the Global and Scene variables the expression tests look up, they are put back once the test ends
+func setExpressionVariables(t *testing.T) {
+	t.Helper()
+	globals, sceneData := GlobalVars, ActiveSceneData
+	t.Cleanup(func() {
+		GlobalVars, ActiveSceneData = globals, sceneData
+	})
+	GlobalVars = NewNFInterfaceMap(
+		NewKeyVal("Name", "Ada"),
+		NewKeyVal("Count", 41),
+		NewKeyVal("Items", []interface{}{"key", "lamp"}),
+	)
+	ActiveSceneData = NewSceneData("Test")
+	ActiveSceneData.Variables.Set("Flag", true)
+	ActiveSceneData.Variables.Set("Speed", 1.5)
+}
+
+func TestExpressionEvaluate(t *testing.T) {
+	setExpressionVariables(t)
+	tests := []struct {
+		name       string
+		expression string
+		want       interface{}
+	}{
+		//Precedence
+		{"multiplication before addition", "1 + 2 * 3", 7},
+		{"parentheses", "(1 + 2) * 3", 9},
+		{"subtraction is left associative", "10 - 4 - 3", 3},
+		{"modulo with multiplication", "2 * 3 % 4", 2},
+		{"division is always a float", "7 / 2", 3.5},
+		{"float arithmetic", "1.5 + 1", 2.5},
+		{"arithmetic before comparison", "1 + 2 == 3", true},
+		{"comparison before equality", "1 < 2 == true", true},
+		{"and before or", "true || false && false", true},
+		{"and before or with words", "false and true or true", true},
+		//Unary operators
+		{"negation", "-3 + 5", 2},
+		{"negation binds tighter than multiplication", "-2 * 3", -6},
+		{"double negation", "--2", 2},
+		{"negated group", "-(2 + 3)", -5},
+		{"negated float", "-1.5", -1.5},
+		{"not", "!true", false},
+		{"not word", "not false", true},
+		{"not zero", "!0", true},
+		{"not empty string", "!''", true},
+		{"not binds tighter than and", "!false && true", true},
+		//Comparisons
+		{"less or equal", "2 <= 2", true},
+		{"greater", "3 > 4", false},
+		{"greater or equal", "4 >= 3.5", true},
+		{"numbers of different types are equal", "1 == 1.0", true},
+		{"strings are not equal", "'a' != 'b'", true},
+		{"string contains", "'abc' contains 'b'", true},
+		{"strings are ordered", "'a' < 'b'", true},
+		//Strings
+		{"double quotes", `"text"`, "text"},
+		{"joined strings", "'a' + 'b'", "ab"},
+		{"string joined with a number", "'a' + 1", "a1"},
+		{"escaped quote", `'it\'s'`, "it's"},
+		//Interpolation
+		{"interpolated lookup", "'Hello {Global.Name}!'", "Hello Ada!"},
+		{"interpolated arithmetic", "'{1 + 2} apples'", "3 apples"},
+		{"interpolated variable that is not set", "'[{Global.Missing}]'", "[]"},
+		{"escaped braces", `'\{literal\}'`, "{literal}"},
+		{"nested interpolation", `'{"{Global.Count}" + "!"}'`, "41!"},
+		//Lookups
+		{"global lookup", "Global.Count + 1", 42},
+		{"scene lookup", "Scene.Flag && true", true},
+		{"scene float lookup", "Scene.Speed * 2", 3.0},
+		{"variable that is not set is nil", "Global.Missing == nil", true},
+		{"list contains", "Global.Items contains 'lamp'", true},
+		//Literals
+		{"nil", "nil", nil},
+		{"true", "true", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewExpression(test.expression).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate(%q) returned an error: %v", test.expression, err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Evaluate(%q) = %#v, want %#v", test.expression, got, test.want)
+			}
+		})
+	}
+}
+
+func TestExpressionErrors(t *testing.T) {
+	setExpressionVariables(t)
+	tests := []struct {
+		name       string
+		expression string
+		reason     string
+	}{
+		{"missing operand", "1 +", "unexpected end of expression"},
+		{"two values", "1 2", "unexpected"},
+		{"missing parenthesis", "(1 + 2", "missing )"},
+		{"unclosed string", "'text", "unclosed string"},
+		{"unclosed interpolation", "'{1 + 2'", "unclosed {"},
+		{"unknown character", "1 $ 2", "unexpected character"},
+		{"unknown name", "Player.Name", "unknown name"},
+		{"bare name", "name", "unknown name"},
+		{"division by zero", "1 / 0", "division by zero"},
+		{"modulo by zero", "1 % 0", "division by zero"},
+		{"arithmetic on a bool", "true * 2", "can not use *"},
+		{"negated string", "-'a'", "can not negate"},
+		{"ordered bool", "true < 1", "true < 1"},
+		{"too long", strings.Repeat("1 + ", MaxExpressionLength/4) + "1", "expression is too long"},
+		{"nested parentheses", strings.Repeat("(", maxExpressionDepth+1) + "1" + strings.Repeat(")", maxExpressionDepth+1), "nested too deeply"},
+		{"nested unary operators", strings.Repeat("-", maxExpressionDepth+1) + "1", "nested too deeply"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewExpression(test.expression).Evaluate()
+			if err == nil {
+				t.Fatalf("Evaluate(%q) did not return an error", test.expression)
+			}
+			if !errors.Is(err, NFError.ErrExpression) {
+				t.Errorf("Evaluate(%q) returned %v, want an ErrExpression", test.expression, err)
+			}
+			if !strings.Contains(err.Error(), test.reason) {
+				t.Errorf("Evaluate(%q) returned %q, want it to contain %q", test.expression, err.Error(), test.reason)
+			}
+		})
+	}
+}
+
+func TestExpressionCheck(t *testing.T) {
+	valid := []string{"1 + 2", "Global.Name == 'Ada'", "'Hello {Global.Name}'", "not (Scene.Flag or false)"}
+	for _, expression := range valid {
+		if err := NewExpression(expression).Check(); err != nil {
+			t.Errorf("Check(%q) returned an error: %v", expression, err)
+		}
+	}
+	invalid := []string{"1 +", "(1", "1 1", "Other.Key", strings.Repeat("1+", MaxExpressionLength)}
+	for _, expression := range invalid {
+		if err := NewExpression(expression).Check(); !errors.Is(err, NFError.ErrExpression) {
+			t.Errorf("Check(%.32q) returned %v, want an ErrExpression", expression, err)
+		}
+	}
+}
+
+func TestExpressionShortCircuit(t *testing.T) {
+	//The right side would fail to evaluate, so these only pass if it is never evaluated
+	for _, expression := range []string{"false && 1 / 0", "true || 1 / 0", "false and -'a'"} {
+		if _, err := NewExpression(expression).Evaluate(); err != nil {
+			t.Errorf("Evaluate(%q) evaluated its right side: %v", expression, err)
+		}
+	}
+}
+
+func TestEvaluateInto(t *testing.T) {
+	setExpressionVariables(t)
+	var i int
+	if err := NewExpression("Global.Count + 1").EvaluateInto(&i); err != nil || i != 42 {
+		t.Errorf("EvaluateInto int = %d, %v, want 42", i, err)
+	}
+	var f float64
+	if err := NewExpression("2").EvaluateInto(&f); err != nil || f != 2 {
+		t.Errorf("EvaluateInto float64 = %v, %v, want 2", f, err)
+	}
+	var s string
+	if err := NewExpression("'{Global.Name}'").EvaluateInto(&s); err != nil || s != "Ada" {
+		t.Errorf("EvaluateInto string = %q, %v, want Ada", s, err)
+	}
+	s = "set"
+	if err := NewExpression("nil").EvaluateInto(&s); err != nil || s != "" {
+		t.Errorf("EvaluateInto nil = %q, %v, want the zero value", s, err)
+	}
+	if err := NewExpression("1").EvaluateInto(i); err == nil {
+		t.Error("EvaluateInto a value that is not a pointer did not return an error")
+	}
+}
+
+func TestAsExpression(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  bool
+	}{
+		{"expression", NewExpression("1"), true},
+		{"pointer", &NFExpression{Expression: "1"}, true},
+		{"nil pointer", (*NFExpression)(nil), false},
+		{"decoded json", map[string]interface{}{"Expression": "1"}, true},
+		{"custom map", CustomMap{"Expression": "1"}, true},
+		{"map with other keys", map[string]interface{}{"Expression": "1", "Other": 2}, false},
+		{"expression that is not a string", map[string]interface{}{"Expression": 1}, false},
+		{"string", "1", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, ok := AsExpression(test.value); ok != test.want {
+				t.Errorf("AsExpression(%#v) = %v, want %v", test.value, ok, test.want)
+			}
+		})
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	setExpressionVariables(t)
+	got, err := Interpolate("{Global.Name} has {Global.Count} \\{coins\\}")
+	if err != nil || got != "Ada has 41 {coins}" {
+		t.Errorf("Interpolate = %q, %v, want %q", got, err, "Ada has 41 {coins}")
+	}
+	if _, err := Interpolate("{1 +}"); !errors.Is(err, NFError.ErrExpression) {
+		t.Errorf("Interpolate of a broken expression returned %v, want an ErrExpression", err)
+	}
+}
@@ -289,7 +289,7 @@ func (m multiFS) Walk(path string, walkFn fs.WalkDirFunc, config Configuration)
 	return err
 }
 
-var embeddedFS multiFS
+var embeddedFS = multiFS{}
 
 // EmbedFS sets the embedded filesystem to use for loading files
 // This function can be called multiple times to add multiple embedded filesystems
@@ -308,6 +308,12 @@ func EmbedFS(fs embed.FS, names ...string) {
 	embeddedFS[name] = fs
 }
 
+// SetFS adds the filesystem under the name, replacing the filesystem that was added with the name before.
+// Unlike EmbedFS it takes any fs.FS, such as os.DirFS of a folder outside the game like the project open in the editor
+func SetFS(name string, fsys fs.FS) {
+	embeddedFS[name] = fsys
+}
+
 // Walk is an extension of fs.WalkDir for any specified fileSystems
 // that have been embedded with EmbedFS
 // or all of them if no names are specified(It will return the first matching file)
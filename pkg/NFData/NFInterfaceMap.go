@@ -132,16 +132,24 @@ func (a *NFInterfaceMap) UnTypedGet(key string) (interface{}, bool) {
 }
 
 // Get gets an element from the interface by reference, it will return an error if the Key does not exist or if the type does not match
+//
+// If the value is an NFExpression it is evaluated and the result is stored in ref instead, unless ref is an NFExpression itself
 func (a *NFInterfaceMap) Get(key string, ref interface{}) error {
 	a.mu.RLock()
-	defer a.mu.RUnlock()
-	if value, ok := a.Data[key]; ok {
+	value, ok := a.Data[key]
+	//The lock is released before evaluating so expressions can look up values in this map
+	a.mu.RUnlock()
+	if ok {
 		//Make sure the ref is a pointer
 		refType := reflect.TypeOf(ref)
 		if refType.Kind() != reflect.Ptr {
 			return errors.New("ref must be a pointer")
 		}
 
+		if expression, isExpression := AsExpression(value); isExpression && refType.Elem() != reflect.TypeOf(NFExpression{}) {
+			return expression.EvaluateInto(ref)
+		}
+
 		//Check if the types match
 		expectedType := reflect.TypeOf(value)
 		actualType := reflect.TypeOf(ref).Elem()
@@ -1,6 +1,7 @@
 package NFLog
 
 import (
+	"fmt"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
@@ -47,6 +48,13 @@ func ShowDialog(window fyne.Window) {
 
 }
 
+// Printf writes a message to the log like log.Printf, once SetUp has run it is also shown in the log dialog
+//
+// The file and line recorded are those of the caller
+func Printf(format string, v ...interface{}) {
+	_ = log.Output(2, fmt.Sprintf(format, v...))
+}
+
 // GetDirectory returns the directory that the log files are stored in
 func GetDirectory() string {
 	return Directory
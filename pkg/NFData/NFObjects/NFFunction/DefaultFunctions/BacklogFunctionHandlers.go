@@ -0,0 +1,52 @@
+package DefaultFunctions
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFScene"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget/DefaultWidgets"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+)
+
+// ShowBacklog opens a dialog listing the lines shown in the active save,
+// if args["AllowRollback"] is true (the default) each line can be rewound to
+func ShowBacklog(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	allowRollback := true
+	_ = args.Get("AllowRollback", &allowRollback)
+	var backlogDialog *dialog.CustomDialog
+	backlog := DefaultWidgets.NewBacklog(window, allowRollback, func() {
+		backlogDialog.Hide()
+	})
+	backlogDialog = dialog.NewCustom("Backlog", "Close", backlog, window)
+	backlogDialog.Resize(fyne.NewSize(window.Canvas().Size().Width*0.8, window.Canvas().Size().Height*0.8))
+	backlogDialog.Show()
+	return args, nil
+}
+
+// Rollback restores the active save to the backlog entry in args["Entry"] and shows the scene its line was on
+//
+// Without an Entry the save is rolled back to the line before the current one.
+// The scene that was shown is returned in "Scene"
+func Rollback(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	if NFSave.Active == nil {
+		return args, NFError.NewErrNotFound("no active save to roll back")
+	}
+	entry := len(NFSave.Active.GetBacklog()) - 2
+	if value, ok := args.UnTypedGet("Entry"); ok {
+		//Scene files store numbers as floats
+		entry, ok = NFData.ToInt(value)
+		if !ok {
+			return args, NFError.NewErrInvalidArgument("Entry", "the entry must be a whole number")
+		}
+	} else if entry < 0 {
+		entry = 0
+	}
+	err := NFScene.Rollback(window, entry)
+	if err != nil {
+		return args, err
+	}
+	args.Set("Scene", NFSave.Active.GetScene())
+	return args, nil
+}
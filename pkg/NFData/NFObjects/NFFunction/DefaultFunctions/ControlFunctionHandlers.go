@@ -0,0 +1,173 @@
+package DefaultFunctions
+
+import (
+	"errors"
+	"fyne.io/fyne/v2"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFFunction"
+	"math/rand"
+	"strconv"
+)
+
+// listArg gets a list argument, lists loaded from json are always []interface{}
+func listArg(args *NFData.NFInterfaceMap, key string) ([]interface{}, error) {
+	value, ok := args.UnTypedGet(key)
+	if !ok {
+		return nil, NFError.NewErrKeyNotFound(key)
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, NFError.NewErrInvalidArgument(key, "must be a list")
+	}
+	return list, nil
+}
+
+// If runs the function call in args["Then"] when args["Condition"] is true, otherwise it runs args["Else"] if it is set
+//
+// The result of the condition is returned in "Result"
+func If(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	condition, _ := args.UnTypedGet("Condition")
+	result, err := NFData.EvaluateCondition(condition)
+	if err != nil {
+		return args, err
+	}
+	args.Set("Result", result)
+	branch := "Then"
+	if !result {
+		branch = "Else"
+	}
+	call, ok := args.UnTypedGet(branch)
+	if !ok || call == nil {
+		return args, nil
+	}
+	_, err = NFFunction.RunCall(window, call)
+	if err != nil {
+		return args, err
+	}
+	return args, nil
+}
+
+// Switch compares the variable at args["Location"] and args["Key"] with each case in args["Cases"] and runs the first match
+//
+// Each case is a map with a "Value", an optional "Operator" ("==" by default) and a "Function" call,
+// if no case matches args["Default"] is run if it is set. The index of the matched case is returned in "Case", -1 when none match
+func Switch(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	ref, err := refFromArgs(args, "Location", "Key")
+	if err != nil {
+		return args, err
+	}
+	cases, err := listArg(args, "Cases")
+	if err != nil {
+		return args, err
+	}
+	value, err := ref.UnTypedGet()
+	if err != nil {
+		if !errors.Is(err, NFError.ErrKeyNotFound) {
+			return args, err
+		}
+		value = nil
+	}
+	for i, c := range cases {
+		caseMap, ok := NFData.AsMap(c)
+		if !ok {
+			return args, NFError.NewErrInvalidArgument("Cases", "case "+strconv.Itoa(i)+" must be a map")
+		}
+		op := NFData.OpEqual
+		if opString, ok := caseMap["Operator"].(string); ok && opString != "" {
+			op = NFData.Operator(opString)
+		}
+		right, err := NFData.Resolve(caseMap["Value"])
+		if err != nil {
+			return args, err
+		}
+		match, err := NFData.Compare(value, op, right)
+		if err != nil {
+			return args, err
+		}
+		if match {
+			args.Set("Case", i)
+			if call, ok := caseMap["Function"]; ok && call != nil {
+				_, err = NFFunction.RunCall(window, call)
+			}
+			return args, err
+		}
+	}
+	args.Set("Case", -1)
+	if call, ok := args.UnTypedGet("Default"); ok && call != nil {
+		_, err = NFFunction.RunCall(window, call)
+	}
+	return args, err
+}
+
+// Sequence runs each function call in args["Functions"] in order
+//
+// By default it stops at the first error, if args["StopOnError"] is false it runs every call and returns all the errors joined
+func Sequence(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	calls, err := listArg(args, "Functions")
+	if err != nil {
+		return args, err
+	}
+	stopOnError := true
+	_ = args.Get("StopOnError", &stopOnError)
+	var fullErr error
+	for _, call := range calls {
+		_, err = NFFunction.RunCall(window, call)
+		if err != nil {
+			if stopOnError {
+				return args, err
+			}
+			fullErr = errors.Join(fullErr, err)
+		}
+	}
+	return args, fullErr
+}
+
+// Random picks one of the options in args["Options"] at random and runs it
+//
+// Each option is a map with a "Function" call and an optional "Weight" (1 by default), options with a higher weight
+// are picked more often and a weight of 0 is never picked. The index of the picked option is returned in "Picked"
+func Random(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	options, err := listArg(args, "Options")
+	if err != nil {
+		return args, err
+	}
+	weights := make([]float64, len(options))
+	total := 0.0
+	for i, option := range options {
+		optionMap, ok := NFData.AsMap(option)
+		if !ok {
+			return args, NFError.NewErrInvalidArgument("Options", "option "+strconv.Itoa(i)+" must be a map")
+		}
+		weights[i] = 1
+		if weight, ok := optionMap["Weight"]; ok {
+			weights[i], ok = NFData.ToFloat(weight)
+			if !ok || weights[i] < 0 {
+				return args, NFError.NewErrInvalidArgument("Weight", "weight must be a positive number")
+			}
+		}
+		total += weights[i]
+	}
+	if total == 0 {
+		return args, NFError.NewErrInvalidArgument("Options", "there are no options with a weight above 0")
+	}
+	pick := rand.Float64() * total
+	picked := len(options) - 1
+	for i, weight := range weights {
+		if pick < weight {
+			picked = i
+			break
+		}
+		pick -= weight
+	}
+	for weights[picked] == 0 {
+		//Guards against float rounding landing on a trailing option that should never be picked
+		picked--
+	}
+	args.Set("Picked", picked)
+	optionMap, _ := NFData.AsMap(options[picked])
+	if call, ok := optionMap["Function"]; ok && call != nil {
+		_, err = NFFunction.RunCall(window, call)
+	}
+	return args, err
+}
@@ -3,13 +3,11 @@ package DefaultFunctions
 import (
 	"errors"
 	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFScene"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
 	"log"
-	"os"
-	"path/filepath"
-	"strings"
-	"time"
+	"strconv"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -81,15 +79,10 @@ func NewGame(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfa
 				err = NFSave.Active.Save()
 				name := "save"
 				if errors.Is(err, NFSave.ErrSaveNameNotSet) {
-					//Walk the directory making sure the save name is unique and if it is not, add a count and check if the count is unique incrementing the count until it is
-					_ = filepath.WalkDir(NFSave.Directory, func(path string, d os.DirEntry, err error) error {
-						//If the file is a saves.saveExtension file, check if the name matches the save name
-						if filepath.Base(path) == name+NFSave.Extension {
-							//If the save name matches, increment the count and return
-							name += "1"
-						}
-						return nil
-					})
+					//Make sure the save name is unique and if it is not, add a count incrementing it until it is
+					for count := 1; NFSave.SaveExists(name); count++ {
+						name = "save" + strconv.Itoa(count)
+					}
 					//Set the save name to the new name
 					NFSave.Active.Name = name
 					//Save the game
@@ -111,15 +104,11 @@ func NewGame(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfa
 	}
 	//Set the active save to the new save
 	NFSave.Active = newSave
-	scene, err := NFScene.Get(newGameScene)
+	NFScene.ClearSceneStack()
+	err = NFScene.ChangeScene(window, newGameScene)
 	if err != nil {
 		return args, err
 	}
-	sceneContent, err := scene.Parse(window)
-	if err != nil {
-		return args, err
-	}
-	window.SetContent(sceneContent)
 
 	return args, nil
 }
@@ -134,30 +123,26 @@ func SaveAs(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfac
 	var confirmDialog *dialog.ConfirmDialog
 	confirmDialog = dialog.NewCustomConfirm("Save As", "Save", "Cancel", saveNameEntry, func(b bool) {
 		if b {
-			//Walk the save file directory making sure the save name is unique, and if it is not, ask if they are sure they want to overwrite the save
-			_ = filepath.WalkDir(NFSave.Directory, func(path string, d os.DirEntry, err error) error {
-				//If the file is a saves.saveExtension file, check if the name matches the save name
-				if filepath.Base(path) == saveNameEntry.Text+NFSave.Extension {
-					//If the save name matches, ask if they are sure they want to overwrite the save
-					dialog.ShowConfirm("Overwrite?", "Are you sure you want to overwrite the save?", func(b bool) {
-						if b {
-							//Set the save name to the entry text
-							NFSave.Active.Name = saveNameEntry.Text
-							//Save the game
-							err = NFSave.Active.Save()
-							if err != nil {
-								args.Set("Error", err.Error())
-								_, _ = CustomError(window, args)
-								return
-							}
-						} else {
-							//Show the save as dialog again
-							confirmDialog.Show()
+			//If the save name is already used by another save, ask if they are sure they want to overwrite the save
+			if saveNameEntry.Text != NFSave.Active.Name && NFSave.SaveExists(saveNameEntry.Text) {
+				dialog.ShowConfirm("Overwrite?", "Are you sure you want to overwrite the save?", func(b bool) {
+					if b {
+						//Set the save name to the entry text
+						NFSave.Active.Name = saveNameEntry.Text
+						//Save the game
+						err := NFSave.Active.Save()
+						if err != nil {
+							args.Set("Error", err.Error())
+							_, _ = CustomError(window, args)
+							return
 						}
-					}, window)
-				}
-				return nil
-			})
+					} else {
+						//Show the save as dialog again
+						confirmDialog.Show()
+					}
+				}, window)
+				return
+			}
 			//Set the save name to the entry text
 			NFSave.Active.Name = saveNameEntry.Text
 			//Save the game
@@ -182,42 +167,30 @@ func SaveAs(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfac
 	return args, nil
 }
 
+// loadNamed makes the named save the active save, if it had to be recovered from a snapshot the player is told
+func loadNamed(window fyne.Window, name string) error {
+	save, err := NFSave.LoadNamed(name)
+	if errors.Is(err, NFError.ErrSaveRecovered) {
+		log.Println(err)
+		dialog.ShowInformation("Save Recovered", "The save "+name+" was damaged, the last good copy of it was loaded instead", window)
+	} else if err != nil {
+		return err
+	}
+	NFSave.Active = save
+	return nil
+}
+
 // LoadGame loads a game save file and starts the game
 func LoadGame(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
-	type FileWithModTime struct {
-		Name    string
-		Path    string
-		ModTime time.Time
-	}
-	var saveFiles []FileWithModTime
-	//Walk the save directory and add each save file to the saves map with the file name being the key and the full path being the value
-	_ = filepath.WalkDir(NFSave.Directory, func(path string, info os.DirEntry, err error) error {
-		//If the file is a saves.saveExtension file, add it to the save map
-		if filepath.Ext(path) == NFSave.Extension {
-			nameWithoutExtension := strings.TrimSuffix(info.Name(), NFSave.Extension)
-			fileInfo, _ := os.Stat(path)
-			saveFiles = append(saveFiles, FileWithModTime{nameWithoutExtension, path, fileInfo.ModTime()})
-		}
-		return nil
-	})
-	//Sort the saves by the last modified time
-	for i := 0; i < len(saveFiles); i++ {
-		for j := 0; j < len(saveFiles); j++ {
-			if saveFiles[i].ModTime.After(saveFiles[j].ModTime) {
-				temp := saveFiles[i]
-				saveFiles[i] = saveFiles[j]
-				saveFiles[j] = temp
-			}
-		}
+	//Get the saves in the save storage, they are sorted with the most recently written first
+	saves, err := NFSave.ListSaves()
+	if err != nil {
+		return args, err
 	}
 	//Create a list of save names
 	var namesList []string
-	for _, file := range saveFiles {
-		namesList = append(namesList, file.Name)
-	}
-	var savesMap = make(map[string]string)
-	for _, file := range saveFiles {
-		savesMap[file.Name] = file.Path
+	for _, save := range saves {
+		namesList = append(namesList, save.Name)
 	}
 	fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
 		if err != nil {
@@ -225,6 +198,10 @@ func LoadGame(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterf
 			_, _ = CustomError(window, args)
 			return
 		}
+		//If the reader is nil, then the user canceled the dialog and nothing should happen
+		if reader == nil {
+			return
+		}
 		//Get the path of the save file and load it
 		path := reader.URI().Path()
 		NFSave.Active, err = NFSave.Load(path)
@@ -233,6 +210,12 @@ func LoadGame(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterf
 			_, _ = CustomError(window, args)
 			return
 		}
+		err = NFScene.ResumeSave(window)
+		if err != nil {
+			args.Set("Error", err.Error())
+			_, _ = CustomError(window, args)
+			return
+		}
 	}, window)
 	saveList := widget.NewList(
 		//Length
@@ -256,44 +239,41 @@ func LoadGame(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterf
 			//Should be a save name with a timestamp and a folder button to choose a history save
 			item := obj.(*fyne.Container)
 			item.Objects[0].(*widget.Label).SetText(namesList[itemID])
-			item.Objects[2].(*widget.Label).SetText(saveFiles[itemID].ModTime.Format("Jan 2 3:04PM"))
+			item.Objects[2].(*widget.Label).SetText(saves[itemID].Time.Format("Jan 2 3:04PM"))
 			item.Objects[3].(*widget.Button).OnTapped = func() {
-				//Get the history files
-				var historyFiles []FileWithModTime
-				parentPath := filepath.Dir(saveFiles[itemID].Path)
-				_ = filepath.WalkDir(parentPath+"/history", func(path string, info os.DirEntry, err error) error {
-					if filepath.Ext(path) == NFSave.Extension+"history" {
-						nameWithoutExtension := strings.TrimSuffix(info.Name(), NFSave.Extension)
-						fileInfo, _ := os.Stat(path)
-						historyFiles = append(historyFiles, FileWithModTime{nameWithoutExtension, path, fileInfo.ModTime()})
-					}
-					return nil
-				})
-				//Sort the saves by the last modified time
-				for i := 0; i < len(historyFiles); i++ {
-					for j := 0; j < len(historyFiles); j++ {
-						if historyFiles[i].ModTime.After(historyFiles[j].ModTime) {
-							temp := historyFiles[i]
-							historyFiles[i] = historyFiles[j]
-							historyFiles[j] = temp
-						}
-					}
+				//Get the snapshots kept in the save's history
+				saveName := saves[itemID].Name
+				snapshots, err := NFSave.ListSnapshots(saveName)
+				if err != nil {
+					args.Set("Error", err.Error())
+					_, _ = CustomError(window, args)
+					return
 				}
-				//Create a list of save names
+				//Create a list of save names with the newest snapshot first
 				var historyNamesList []string
-				var historySaveMap = make(map[string]string)
+				var historySnapshotMap = make(map[string]int)
 				//Add the default save named Latest save to the list
 				historyNamesList = append(historyNamesList, "Latest Save")
-				historySaveMap["Latest Save"] = saveFiles[itemID].Path
-				for _, file := range historyFiles {
-					historyNamesList = append(historyNamesList, file.Name+" - "+file.ModTime.Format("Jan 2 3:04PM"))
-					historySaveMap[file.Name+" - "+file.ModTime.Format("Jan 2 3:04PM")] = file.Path
+				for i := len(snapshots) - 1; i >= 0; i-- {
+					snapshotName := "Snapshot " + strconv.Itoa(snapshots[i].Number) + " - " + snapshots[i].Time.Format("Jan 2 3:04PM")
+					historyNamesList = append(historyNamesList, snapshotName)
+					historySnapshotMap[snapshotName] = snapshots[i].Number
 				}
 				dialog.ShowCustom("History", "Close", container.NewVBox(
 					widget.NewLabel("Select a save file to load"),
 					widget.NewSelect(historyNamesList, func(s string) {
 						var err error
-						NFSave.Active, err = NFSave.Load(historySaveMap[s])
+						if number, ok := historySnapshotMap[s]; ok {
+							err = NFSave.RestoreSnapshot(saveName, number)
+						} else {
+							err = loadNamed(window, saveName)
+						}
+						if err != nil {
+							args.Set("Error", err.Error())
+							_, _ = CustomError(window, args)
+							return
+						}
+						err = NFScene.ResumeSave(window)
 						if err != nil {
 							args.Set("Error", err.Error())
 							_, _ = CustomError(window, args)
@@ -319,46 +299,30 @@ func LoadGame(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterf
 
 // ContinueGame continues the game from the last save file
 func ContinueGame(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
-	//Walk the save directory
-	type FileWithModTime struct {
-		Name    string
-		Path    string
-		ModTime time.Time
+	//Get the saves in the save storage, they are sorted with the most recently written first
+	saves, err := NFSave.ListSaves()
+	if err != nil {
+		args.Set("Error", err.Error())
+		_, _ = CustomError(window, args)
+		return args, err
 	}
-	var saveFiles []FileWithModTime
-	//Walk the save directory and add each save file to the saves map with the file name being the key and the full path being the value
-	_ = filepath.WalkDir(NFSave.Directory, func(path string, info os.DirEntry, err error) error {
-		//If the file is a saves.saveExtension file, add it to the save map
-		if filepath.Ext(path) == NFSave.Extension {
-			nameWithoutExtension := strings.TrimSuffix(info.Name(), NFSave.Extension)
-			fileInfo, _ := os.Stat(path)
-			saveFiles = append(saveFiles, FileWithModTime{nameWithoutExtension, path, fileInfo.ModTime()})
-		}
-		return nil
-	})
 
 	//if there are no save files, return an error
-	if len(saveFiles) == 0 {
+	if len(saves) == 0 {
 		err := errors.New("no save files found")
 		args.Set("Error", err.Error())
 		_, _ = CustomError(window, args)
 		return args, err
 	}
 
-	//Sort the saves by the last modified time
-	for i := 0; i < len(saveFiles); i++ {
-		for j := 0; j < len(saveFiles); j++ {
-			if saveFiles[i].ModTime.After(saveFiles[j].ModTime) {
-				temp := saveFiles[i]
-				saveFiles[i] = saveFiles[j]
-				saveFiles[j] = temp
-			}
-		}
-	}
-
 	//Open the latest save file
-	var err error
-	NFSave.Active, err = NFSave.Load(saveFiles[0].Path)
+	err = loadNamed(window, saves[0].Name)
+	if err != nil {
+		args.Set("Error", err.Error())
+		_, _ = CustomError(window, args)
+		return args, err
+	}
+	err = NFScene.ResumeSave(window)
 	if err != nil {
 		args.Set("Error", err.Error())
 		_, _ = CustomError(window, args)
@@ -69,4 +69,250 @@ func init() {
 		OptionalArgs: NFData.NewNFInterfaceMap(),
 	}
 	continueGame.Register(ContinueGame)
+
+	changeScene := NFFunction.Function{
+		Type:         "ChangeScene",
+		RequiredArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Scene", "This should be the name of the scene to change to. THIS IS CASE SENSITIVE")),
+		OptionalArgs: withTransitionArgs(NFData.NewKeyVal("ClearStack", false)),
+	}
+	changeScene.Register(ChangeScene)
+
+	pushScene := NFFunction.Function{
+		Type:         "PushScene",
+		RequiredArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Scene", "This should be the name of the scene to push. THIS IS CASE SENSITIVE")),
+		OptionalArgs: withTransitionArgs(),
+	}
+	pushScene.Register(PushScene)
+
+	popScene := NFFunction.Function{
+		Type:         "PopScene",
+		RequiredArgs: NFData.NewNFInterfaceMap(),
+		OptionalArgs: withTransitionArgs(NFData.NewKeyVal("FallbackScene", "This should be the name of the scene to show if there is no scene to return to")),
+	}
+	popScene.Register(PopScene)
+
+	preloadScenes := NFFunction.Function{
+		Type:         "PreloadScenes",
+		RequiredArgs: NFData.NewNFInterfaceMap(),
+		OptionalArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Scenes", []interface{}{"This should be the name of a scene to preload. THIS IS CASE SENSITIVE"}),
+			NFData.NewKeyVal("Reachable", false),
+		),
+	}
+	preloadScenes.Register(PreloadScenes)
+
+	showOverlay := NFFunction.Function{
+		Type:         "ShowOverlay",
+		RequiredArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Overlay", "This should be the name of the overlay to show. THIS IS CASE SENSITIVE")),
+		OptionalArgs: NFData.NewNFInterfaceMap(),
+	}
+	showOverlay.Register(ShowOverlay)
+
+	hideOverlay := NFFunction.Function{
+		Type:         "HideOverlay",
+		RequiredArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Overlay", "This should be the name of the overlay to hide. THIS IS CASE SENSITIVE")),
+		OptionalArgs: NFData.NewNFInterfaceMap(),
+	}
+	hideOverlay.Register(HideOverlay)
+
+	toggleOverlay := NFFunction.Function{
+		Type:         "ToggleOverlay",
+		RequiredArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Overlay", "This should be the name of the overlay to show or hide. THIS IS CASE SENSITIVE")),
+		OptionalArgs: NFData.NewNFInterfaceMap(),
+	}
+	toggleOverlay.Register(ToggleOverlay)
+
+	showBacklog := NFFunction.Function{
+		Type:         "ShowBacklog",
+		RequiredArgs: NFData.NewNFInterfaceMap(),
+		OptionalArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("AllowRollback", true)),
+	}
+	showBacklog.Register(ShowBacklog)
+
+	rollback := NFFunction.Function{
+		Type:         "Rollback",
+		RequiredArgs: NFData.NewNFInterfaceMap(),
+		OptionalArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Entry", 0)),
+	}
+	rollback.Register(Rollback)
+
+	showCharacter := NFFunction.Function{
+		Type:         "ShowCharacter",
+		RequiredArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Character", "This should be the ID of the character to show")),
+		OptionalArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Slot", "This should be the stage slot to show the character in, left, center, right or a custom slot"),
+			NFData.NewKeyVal("Expression", "This should be the name of the expression to show, the default expression is used if it is empty"),
+			NFData.NewKeyVal("StageKey", "This should be the StageKey of the stage, Stage by default"),
+			NFData.NewKeyVal("Transition", "This should be one of none, fade or slide"),
+			NFData.NewKeyVal("Duration", 0.3),
+		),
+	}
+	showCharacter.Register(ShowCharacter)
+
+	hideCharacter := NFFunction.Function{
+		Type:         "HideCharacter",
+		RequiredArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Character", "This should be the ID of the character to hide")),
+		OptionalArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("StageKey", "This should be the StageKey of the stage, Stage by default"),
+			NFData.NewKeyVal("Transition", "This should be one of none, fade or slide"),
+			NFData.NewKeyVal("Duration", 0.3),
+		),
+	}
+	hideCharacter.Register(HideCharacter)
+
+	setExpression := NFFunction.Function{
+		Type: "SetExpression",
+		RequiredArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Character", "This should be the ID of the character on the stage"),
+			NFData.NewKeyVal("Expression", "This should be the name of the expression to show"),
+		),
+		OptionalArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("StageKey", "This should be the StageKey of the stage, Stage by default")),
+	}
+	setExpression.Register(SetExpression)
+
+	saveSlot := NFFunction.Function{
+		Type:         "SaveSlot",
+		RequiredArgs: NFData.NewNFInterfaceMap(),
+		OptionalArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Slot", "This should be the slot to save to, the first empty slot is used if it is not set")),
+	}
+	saveSlot.Register(SaveSlot)
+
+	loadSlot := NFFunction.Function{
+		Type:         "LoadSlot",
+		RequiredArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Slot", "This should be the slot to load")),
+		OptionalArgs: NFData.NewNFInterfaceMap(),
+	}
+	loadSlot.Register(LoadSlot)
+
+	quickSave := NFFunction.Function{
+		Type:         "QuickSave",
+		RequiredArgs: NFData.NewNFInterfaceMap(),
+		OptionalArgs: NFData.NewNFInterfaceMap(),
+	}
+	quickSave.Register(QuickSave)
+
+	quickLoad := NFFunction.Function{
+		Type:         "QuickLoad",
+		RequiredArgs: NFData.NewNFInterfaceMap(),
+		OptionalArgs: NFData.NewNFInterfaceMap(),
+	}
+	quickLoad.Register(QuickLoad)
+
+	deleteSlot := NFFunction.Function{
+		Type:         "DeleteSlot",
+		RequiredArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Slot", "This should be the slot to delete")),
+		OptionalArgs: NFData.NewNFInterfaceMap(),
+	}
+	deleteSlot.Register(DeleteSlot)
+
+	showSaveSlots := NFFunction.Function{
+		Type:         "ShowSaveSlots",
+		RequiredArgs: NFData.NewNFInterfaceMap(),
+		OptionalArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Mode", "This should be save or load"),
+			NFData.NewKeyVal("Slots", 12),
+			NFData.NewKeyVal("Columns", 3),
+		),
+	}
+	showSaveSlots.Register(ShowSaveSlots)
+
+	setVar := NFFunction.Function{
+		Type: "SetVar",
+		RequiredArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Location", "This should be where the variable is stored, one of Global, Scene, Save or Persistent"),
+			NFData.NewKeyVal("Key", "This should be the name of the variable"),
+			NFData.NewKeyVal("Value", "This should be the new value of the variable"),
+		),
+		OptionalArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Type", "This should be the type to convert the value to, one of Int, Float, Bool or String")),
+	}
+	setVar.Register(SetVar)
+
+	incrementVar := NFFunction.Function{
+		Type: "IncrementVar",
+		RequiredArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Location", "This should be where the variable is stored, one of Global, Scene, Save or Persistent"),
+			NFData.NewKeyVal("Key", "This should be the name of the variable"),
+		),
+		OptionalArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Amount", 1)),
+	}
+	incrementVar.Register(IncrementVar)
+
+	toggleVar := NFFunction.Function{
+		Type: "ToggleVar",
+		RequiredArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Location", "This should be where the variable is stored, one of Global, Scene, Save or Persistent"),
+			NFData.NewKeyVal("Key", "This should be the name of the variable"),
+		),
+		OptionalArgs: NFData.NewNFInterfaceMap(),
+	}
+	toggleVar.Register(ToggleVar)
+
+	copyVar := NFFunction.Function{
+		Type: "CopyVar",
+		RequiredArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("FromLocation", "This should be where the variable to copy is stored, one of Global, Scene, Save or Persistent"),
+			NFData.NewKeyVal("FromKey", "This should be the name of the variable to copy"),
+			NFData.NewKeyVal("Location", "This should be where the copy is stored, one of Global, Scene, Save or Persistent"),
+			NFData.NewKeyVal("Key", "This should be the name of the copy"),
+		),
+		OptionalArgs: NFData.NewNFInterfaceMap(),
+	}
+	copyVar.Register(CopyVar)
+
+	clearVar := NFFunction.Function{
+		Type: "ClearVar",
+		RequiredArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Location", "This should be where the variable is stored, one of Global, Scene, Save or Persistent"),
+			NFData.NewKeyVal("Key", "This should be the name of the variable"),
+		),
+		OptionalArgs: NFData.NewNFInterfaceMap(),
+	}
+	clearVar.Register(ClearVar)
+
+	ifFunction := NFFunction.Function{
+		Type: "If",
+		RequiredArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Condition", map[string]interface{}{"Location": "Global", "Key": "", "Operator": "==", "Value": ""}),
+			NFData.NewKeyVal("Then", map[string]interface{}{"Type": "", "Args": map[string]interface{}{}}),
+		),
+		OptionalArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Else", map[string]interface{}{"Type": "", "Args": map[string]interface{}{}})),
+	}
+	ifFunction.Register(If)
+
+	switchFunction := NFFunction.Function{
+		Type: "Switch",
+		RequiredArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Location", "This should be where the variable is stored, one of Global, Scene, Save or Persistent"),
+			NFData.NewKeyVal("Key", "This should be the name of the variable"),
+			NFData.NewKeyVal("Cases", []interface{}{map[string]interface{}{"Operator": "==", "Value": "", "Function": map[string]interface{}{"Type": "", "Args": map[string]interface{}{}}}}),
+		),
+		OptionalArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Default", map[string]interface{}{"Type": "", "Args": map[string]interface{}{}})),
+	}
+	switchFunction.Register(Switch)
+
+	sequence := NFFunction.Function{
+		Type:         "Sequence",
+		RequiredArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Functions", []interface{}{map[string]interface{}{"Type": "", "Args": map[string]interface{}{}}})),
+		OptionalArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("StopOnError", true)),
+	}
+	sequence.Register(Sequence)
+
+	random := NFFunction.Function{
+		Type:         "Random",
+		RequiredArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("Options", []interface{}{map[string]interface{}{"Weight": 1, "Function": map[string]interface{}{"Type": "", "Args": map[string]interface{}{}}}})),
+		OptionalArgs: NFData.NewNFInterfaceMap(),
+	}
+	random.Register(Random)
+}
+
+// withTransitionArgs returns the optional args of a navigation function with the transition args added, see NFScene.ParseTransition
+func withTransitionArgs(keyVals ...NFData.NFKeyVal) *NFData.NFInterfaceMap {
+	return NFData.NewNFInterfaceMap(append(keyVals,
+		NFData.NewKeyVal("Transition", "This should be the name of a registered transition such as none, fade, crossfade, dissolve, slide or wipe"),
+		NFData.NewKeyVal("TransitionDuration", 0.5),
+		NFData.NewKeyVal("TransitionEasing", "This should be one of linear, easeIn, easeOut or easeInOut"),
+		NFData.NewKeyVal("TransitionColor", "#000000"),
+		NFData.NewKeyVal("TransitionDirection", "This should be one of left, right, up or down"),
+		NFData.NewKeyVal("TransitionMask", "This should be the path of a grayscale image used by the wipe transition"),
+	)...)
 }
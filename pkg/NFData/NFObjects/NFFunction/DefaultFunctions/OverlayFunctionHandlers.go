@@ -0,0 +1,43 @@
+package DefaultFunctions
+
+import (
+	"fyne.io/fyne/v2"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFScene"
+)
+
+// ShowOverlay shows the overlay in args["Overlay"] over the current scene
+func ShowOverlay(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	var overlay string
+	err := args.Get("Overlay", &overlay)
+	if err != nil {
+		return args, err
+	}
+	return args, NFScene.ShowOverlay(window, overlay)
+}
+
+// HideOverlay hides the overlay in args["Overlay"]
+func HideOverlay(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	var overlay string
+	err := args.Get("Overlay", &overlay)
+	if err != nil {
+		return args, err
+	}
+	return args, NFScene.HideOverlay(window, overlay)
+}
+
+// ToggleOverlay shows the overlay in args["Overlay"] if it is hidden and hides it if it is shown,
+// whether it is now shown is returned in "Visible"
+func ToggleOverlay(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	var overlay string
+	err := args.Get("Overlay", &overlay)
+	if err != nil {
+		return args, err
+	}
+	visible, err := NFScene.ToggleOverlay(window, overlay)
+	if err != nil {
+		return args, err
+	}
+	args.Set("Visible", visible)
+	return args, nil
+}
@@ -0,0 +1,111 @@
+package DefaultFunctions
+
+import (
+	"errors"
+	"fyne.io/fyne/v2"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFScene"
+)
+
+// ChangeScene replaces the current scene with the scene in args["Scene"]
+//
+// If args["ClearStack"] is true the navigation stack is emptied so that PopScene can not return to older scenes.
+// The transition args are read with NFScene.ParseTransition
+func ChangeScene(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	var scene string
+	err := args.Get("Scene", &scene)
+	if err != nil {
+		return args, err
+	}
+	transition, err := transitionArgs(args)
+	if err != nil {
+		return args, err
+	}
+	var clearStack bool
+	_ = args.Get("ClearStack", &clearStack)
+	if clearStack {
+		NFScene.ClearSceneStack()
+	}
+	err = NFScene.ChangeScene(window, scene, transition...)
+	if err != nil {
+		return args, err
+	}
+	return args, nil
+}
+
+// PushScene changes to the scene in args["Scene"] while remembering the current scene so PopScene can return to it
+func PushScene(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	var scene string
+	err := args.Get("Scene", &scene)
+	if err != nil {
+		return args, err
+	}
+	transition, err := transitionArgs(args)
+	if err != nil {
+		return args, err
+	}
+	err = NFScene.PushScene(window, scene, transition...)
+	if err != nil {
+		return args, err
+	}
+	return args, nil
+}
+
+// PopScene returns to the scene that last called PushScene
+//
+// If there is no scene to return to and args["FallbackScene"] is set, it changes to the fallback scene instead.
+// The name of the scene that was shown is returned in "Scene"
+func PopScene(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	transition, err := transitionArgs(args)
+	if err != nil {
+		return args, err
+	}
+	scene, err := NFScene.PopScene(window, transition...)
+	if err != nil {
+		var fallback string
+		if !errors.Is(err, NFError.ErrNotFound) || args.Get("FallbackScene", &fallback) != nil || fallback == "" {
+			return args, err
+		}
+		err = NFScene.ChangeScene(window, fallback, transition...)
+		if err != nil {
+			return args, err
+		}
+		scene = fallback
+	}
+	args.Set("Scene", scene)
+	return args, nil
+}
+
+// PreloadScenes reads the scenes in args["Scenes"] and their assets in the background so they show without waiting,
+// if args["Reachable"] is true every scene that can be reached from the current scene is preloaded as well
+func PreloadScenes(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	var scenes []interface{}
+	_ = args.Get("Scenes", &scenes)
+	names := make([]string, 0, len(scenes))
+	for _, scene := range scenes {
+		name, ok := scene.(string)
+		if !ok {
+			return args, NFError.NewErrInvalidArgument("Scenes", "the scenes must be a list of scene names")
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	NFScene.Preload(names...)
+	var reachable bool
+	if args.Get("Reachable", &reachable) == nil && reachable {
+		NFScene.PreloadReachable()
+	}
+	return args, nil
+}
+
+// transitionArgs reads the transition in the args of a navigation function,
+// nothing is returned if the args do not name one so the transition of the scene is used
+func transitionArgs(args *NFData.NFInterfaceMap) ([]NFScene.TransitionOptions, error) {
+	options, ok, err := NFScene.ParseTransition(args)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return []NFScene.TransitionOptions{options}, nil
+}
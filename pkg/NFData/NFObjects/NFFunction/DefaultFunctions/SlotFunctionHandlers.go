@@ -0,0 +1,145 @@
+package DefaultFunctions
+
+import (
+	"errors"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFScene"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget/DefaultWidgets"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+	"image"
+	"log"
+	"strconv"
+)
+
+// slotArg reads args["Slot"], which can be a name or a number
+func slotArg(args *NFData.NFInterfaceMap) (string, bool) {
+	value, ok := args.UnTypedGet("Slot")
+	if !ok {
+		return "", false
+	}
+	if number, isNumber := NFData.ToInt(value); isNumber {
+		return strconv.Itoa(number), true
+	}
+	slot, ok := value.(string)
+	return slot, ok && slot != ""
+}
+
+// SaveSlot saves the active save in to args["Slot"], or the first empty slot, with a thumbnail of the window
+//
+// The slot that was saved to is returned in "Slot"
+func SaveSlot(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	if NFSave.Active == nil {
+		return args, NFError.NewErrNotFound("no active save to save")
+	}
+	slot, ok := slotArg(args)
+	if !ok {
+		slot = NFSave.NextSlotID()
+	}
+	var thumbnail image.Image
+	if window != nil {
+		thumbnail = window.Canvas().Capture()
+	}
+	_, err := NFSave.WriteSlot(slot, NFSave.Active, thumbnail)
+	if err != nil {
+		return args, err
+	}
+	args.Set("Slot", slot)
+	return args, nil
+}
+
+// LoadSlot makes the save in args["Slot"] the active save and resumes it
+func LoadSlot(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	slot, ok := slotArg(args)
+	if !ok {
+		return args, NFError.NewErrMissingArgument("LoadSlot", "Slot")
+	}
+	save, err := NFSave.LoadSlot(slot)
+	err = resumeSlot(window, slot, save, err)
+	if err != nil {
+		return args, err
+	}
+	return args, nil
+}
+
+// resumeSlot makes the save loaded from the slot the active save and resumes it,
+// if it had to be recovered from the slot's last good save the player is told
+func resumeSlot(window fyne.Window, slot string, save *NFSave.Save, err error) error {
+	if errors.Is(err, NFError.ErrSaveRecovered) {
+		log.Println(err)
+		dialog.ShowInformation("Save Recovered", "The save in slot "+slot+" was damaged, the last good copy of it was loaded instead", window)
+	} else if err != nil {
+		return err
+	}
+	NFSave.Active = save
+	return NFScene.ResumeSave(window)
+}
+
+// QuickSave saves the active save in to the quick save slot with a thumbnail of the window
+func QuickSave(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	var thumbnail image.Image
+	if window != nil {
+		thumbnail = window.Canvas().Capture()
+	}
+	_, err := NFSave.QuickSave(thumbnail)
+	if err != nil {
+		return args, err
+	}
+	return args, nil
+}
+
+// QuickLoad makes the save in the quick save slot the active save and resumes it
+func QuickLoad(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	save, err := NFSave.QuickLoad()
+	err = resumeSlot(window, NFSave.QuickSlotID, save, err)
+	if err != nil {
+		return args, err
+	}
+	return args, nil
+}
+
+// DeleteSlot deletes the save in args["Slot"]
+func DeleteSlot(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	slot, ok := slotArg(args)
+	if !ok {
+		return args, NFError.NewErrMissingArgument("DeleteSlot", "Slot")
+	}
+	err := NFSave.DeleteSlot(slot)
+	if err != nil {
+		return args, err
+	}
+	return args, nil
+}
+
+// ShowSaveSlots opens a dialog with a grid of save slots, args["Mode"] is either save or load
+//
+// The thumbnail for saving is captured before the dialog covers the window
+func ShowSaveSlots(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	mode := DefaultWidgets.SlotModeLoad
+	_ = args.Get("Mode", &mode)
+	count, columns := 12, 3
+	if value, ok := args.UnTypedGet("Slots"); ok {
+		count, _ = NFData.ToInt(value)
+	}
+	if value, ok := args.UnTypedGet("Columns"); ok {
+		columns, _ = NFData.ToInt(value)
+	}
+	var thumbnail image.Image
+	if mode == DefaultWidgets.SlotModeSave {
+		thumbnail = window.Canvas().Capture()
+	}
+	var slotsDialog *dialog.CustomDialog
+	slots := DefaultWidgets.NewSaveSlots(window, mode, count, columns, thumbnail, func() {
+		slotsDialog.Hide()
+	})
+	title := "Load Game"
+	if mode == DefaultWidgets.SlotModeSave {
+		title = "Save Game"
+	}
+	slotsDialog = dialog.NewCustom(title, "Close", slots, window)
+	slotsDialog.Resize(fyne.NewSize(window.Canvas().Size().Width*0.9, window.Canvas().Size().Height*0.9))
+	slotsDialog.Show()
+	return args, nil
+}
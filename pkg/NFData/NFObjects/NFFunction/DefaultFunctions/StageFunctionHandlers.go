@@ -0,0 +1,76 @@
+package DefaultFunctions
+
+import (
+	"fyne.io/fyne/v2"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget/CalsWidgets"
+	"time"
+)
+
+// stageArgs reads the args shared by the stage functions, args["StageKey"] is the StageKey of the Stage widget
+// and defaults to CalsWidgets.DefaultStageKey, the transition defaults to fade and the duration to 0.3 seconds
+func stageArgs(args *NFData.NFInterfaceMap) (key, character, transition string, duration time.Duration, err error) {
+	err = args.Get("Character", &character)
+	if err != nil {
+		return
+	}
+	key = CalsWidgets.DefaultStageKey
+	_ = args.Get("StageKey", &key)
+	transition = CalsWidgets.TransitionFade
+	_ = args.Get("Transition", &transition)
+	seconds := 0.3
+	_ = args.Get("Duration", &seconds)
+	duration = time.Duration(seconds * float64(time.Second))
+	return
+}
+
+// ShowCharacter shows the character in args["Character"] in args["Slot"] (center by default) of the stage,
+// with the expression in args["Expression"] or the character's default expression
+//
+// A character that is already on the stage changes its expression and moves to the slot
+func ShowCharacter(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	key, character, transition, duration, err := stageArgs(args)
+	if err != nil {
+		return args, err
+	}
+	slot := "center"
+	_ = args.Get("Slot", &slot)
+	var expression string
+	_ = args.Get("Expression", &expression)
+	err = CalsWidgets.ShowCharacter(key, character, slot, expression, transition, duration)
+	if err != nil {
+		return args, err
+	}
+	return args, nil
+}
+
+// HideCharacter takes the character in args["Character"] off the stage
+func HideCharacter(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	key, character, transition, duration, err := stageArgs(args)
+	if err != nil {
+		return args, err
+	}
+	err = CalsWidgets.HideCharacter(key, character, transition, duration)
+	if err != nil {
+		return args, err
+	}
+	return args, nil
+}
+
+// SetExpression changes the expression of the character in args["Character"] to args["Expression"]
+func SetExpression(window fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	key, character, _, _, err := stageArgs(args)
+	if err != nil {
+		return args, err
+	}
+	var expression string
+	err = args.Get("Expression", &expression)
+	if err != nil {
+		return args, err
+	}
+	err = CalsWidgets.SetExpression(key, character, expression)
+	if err != nil {
+		return args, err
+	}
+	return args, nil
+}
@@ -0,0 +1,167 @@
+package DefaultFunctions
+
+import (
+	"fyne.io/fyne/v2"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"slices"
+)
+
+// refFromArgs builds an NFReference from the location and key arguments, the location must be one of NFData.GetRefTypes
+func refFromArgs(args *NFData.NFInterfaceMap, locationKey, keyKey string) (NFData.NFReference, error) {
+	var location string
+	err := args.Get(locationKey, &location)
+	if err != nil {
+		return NFData.NFReference{}, err
+	}
+	if !slices.Contains(NFData.GetRefTypes(), NFData.Type(location)) {
+		return NFData.NFReference{}, NFError.NewErrInvalidArgument(locationKey, "unknown location "+location)
+	}
+	var key string
+	err = args.Get(keyKey, &key)
+	if err != nil {
+		return NFData.NFReference{}, err
+	}
+	if key == "" {
+		return NFData.NFReference{}, NFError.NewErrInvalidArgument(keyKey, "key can not be empty")
+	}
+	return NFData.NewRef(NFData.Type(location), key), nil
+}
+
+// SetVar sets the variable at args["Location"] and args["Key"] to args["Value"]
+//
+// If args["Type"] is set to one of the NFData.ValueType names the value is converted to that type first,
+// this is needed for ints as all numbers in scene files are loaded as floats.
+// The value can be an NFData.NFExpression to calculate it from other variables
+func SetVar(_ fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	ref, err := refFromArgs(args, "Location", "Key")
+	if err != nil {
+		return args, err
+	}
+	value, ok := args.UnTypedGet("Value")
+	if !ok {
+		return args, NFError.NewErrMissingArgument("SetVar", "Value")
+	}
+	value, err = NFData.Evaluate(value)
+	if err != nil {
+		return args, err
+	}
+	var valueType string
+	if args.Get("Type", &valueType) == nil && valueType != "" {
+		value, err = NFData.ConvertValue(value, NFData.GetType(valueType))
+		if err != nil {
+			return args, err
+		}
+	}
+	err = ref.Set(value)
+	if err != nil {
+		return args, err
+	}
+	args.Set("Value", value)
+	return args, nil
+}
+
+// IncrementVar adds args["Amount"] (1 by default) to the numeric variable at args["Location"] and args["Key"]
+//
+// Variables that are not set yet start at 0, and ints stay ints as long as the amount is a whole number
+func IncrementVar(_ fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	ref, err := refFromArgs(args, "Location", "Key")
+	if err != nil {
+		return args, err
+	}
+	var amount interface{} = 1
+	if value, ok := args.UnTypedGet("Amount"); ok {
+		amount, err = NFData.Evaluate(value)
+		if err != nil {
+			return args, err
+		}
+	}
+	amountFloat, ok := NFData.ToFloat(amount)
+	if !ok {
+		return args, NFError.NewErrInvalidArgument("Amount", "amount must be a number")
+	}
+	var current interface{} = 0
+	if value, err := ref.UnTypedGet(); err == nil {
+		current = value
+	}
+	var newValue interface{}
+	if _, isInt := current.(int); isInt {
+		amountInt, amountIsInt := NFData.ToInt(amount)
+		if amountIsInt {
+			newValue = current.(int) + amountInt
+		}
+	}
+	if newValue == nil {
+		currentFloat, ok := NFData.ToFloat(current)
+		if !ok {
+			return args, NFError.NewErrTypeMismatch("number", NFData.GetValueType(current).String())
+		}
+		newValue = currentFloat + amountFloat
+	}
+	err = ref.Set(newValue)
+	if err != nil {
+		return args, err
+	}
+	args.Set("Value", newValue)
+	return args, nil
+}
+
+// ToggleVar flips the bool variable at args["Location"] and args["Key"], variables that are not set yet become true
+func ToggleVar(_ fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	ref, err := refFromArgs(args, "Location", "Key")
+	if err != nil {
+		return args, err
+	}
+	current := false
+	if value, err := ref.UnTypedGet(); err == nil {
+		boolean, ok := value.(bool)
+		if !ok {
+			return args, NFError.NewErrTypeMismatch(NFData.BooleanType.String(), NFData.GetValueType(value).String())
+		}
+		current = boolean
+	}
+	err = ref.Set(!current)
+	if err != nil {
+		return args, err
+	}
+	args.Set("Value", !current)
+	return args, nil
+}
+
+// CopyVar copies the variable at args["FromLocation"] and args["FromKey"] to args["Location"] and args["Key"]
+func CopyVar(_ fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	from, err := refFromArgs(args, "FromLocation", "FromKey")
+	if err != nil {
+		return args, err
+	}
+	to, err := refFromArgs(args, "Location", "Key")
+	if err != nil {
+		return args, err
+	}
+	value, err := from.UnTypedGet()
+	if err != nil {
+		return args, err
+	}
+	if copyable, ok := value.(NFData.Copyable); ok {
+		value = copyable.Copy()
+	}
+	err = to.Set(value)
+	if err != nil {
+		return args, err
+	}
+	args.Set("Value", value)
+	return args, nil
+}
+
+// ClearVar removes the variable at args["Location"] and args["Key"], bindings with the same key are reset to their zero value
+func ClearVar(_ fyne.Window, args *NFData.NFInterfaceMap) (*NFData.NFInterfaceMap, error) {
+	ref, err := refFromArgs(args, "Location", "Key")
+	if err != nil {
+		return args, err
+	}
+	err = ref.Delete()
+	if err != nil {
+		return args, err
+	}
+	return args, nil
+}
@@ -165,6 +165,29 @@ func ParseAndRun(window fyne.Window, function string, args *NFData.NFInterfaceMa
 	}
 }
 
+// RunCall runs a nested function call, this is a map with the function "Type" and its "Args"
+//
+// This is used by functions that run other functions, such as If and Sequence, as their Args are loaded from json
+func RunCall(window fyne.Window, call interface{}) (*NFData.NFInterfaceMap, error) {
+	callMap, ok := NFData.AsMap(call)
+	if !ok {
+		return nil, NFError.NewErrInvalidArgument("function call", "call must be a map with a Type and Args")
+	}
+	functionType, ok := callMap["Type"].(string)
+	if !ok || functionType == "" {
+		return nil, NFError.NewErrMissingArgument("function call", "Type")
+	}
+	args := NFData.NewNFInterfaceMap()
+	if rawArgs, ok := callMap["Args"]; ok && rawArgs != nil {
+		argsMap, ok := NFData.AsMap(rawArgs)
+		if !ok {
+			return nil, NFError.NewErrInvalidArgument("Args", "args must be a map")
+		}
+		args = NFData.NewNFInterfaceFromMap(argsMap)
+	}
+	return ParseAndRun(window, functionType, args)
+}
+
 // Register adds a custom function to the Functions map
 func (f *Function) Register(handler functionHandler) {
 	//Check if the name is already registered, if it is, return
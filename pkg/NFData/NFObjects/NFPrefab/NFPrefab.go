@@ -0,0 +1,436 @@
+// Package NFPrefab holds prefabs, widget or layout subtrees saved once in a .NFPrefab file
+// and instanced by name from scenes with the Prefab widget, so shared pieces such as a dialogue frame or HUD are only made once.
+//
+// Instances only hold the name of the prefab and the args they override, the prefab is read when the instance is parsed,
+// so editing a prefab updates every scene that uses it
+package NFPrefab
+
+import (
+	"encoding/json"
+	"errors"
+	"fyne.io/fyne/v2"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFFS"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFLayout"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Extension is the file extension used for prefab files
+const Extension = ".NFPrefab"
+
+// Directory is where the prefabs of a game are kept, relative to the game directory
+const Directory = "data/prefabs"
+
+// WidgetType is the type of the widget that instances a prefab,
+// its "Prefab" arg is the name of the prefab and "Overrides" maps the names of widgets in the prefab to args they are given.
+// Any other args are set on the root widget or layout of the prefab
+const WidgetType = "Prefab"
+
+// PrefabMap maps prefab names to the path of their file
+var PrefabMap = map[string]string{}
+
+// cache holds the files of the prefabs read by Get, every instance decodes its own copy from them
+var cache = map[string][]byte{}
+var cacheLock sync.Mutex
+
+func init() {
+	prefab := NFWidget.Widget{
+		Type: WidgetType,
+		RequiredArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Prefab", ""),
+		),
+		OptionalArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Overrides", NFData.NewNFInterfaceMap()),
+		),
+	}
+	prefab.Register(widgetHandler)
+}
+
+// Prefab is a saved widget or layout subtree, only one of Widget and Layout is set
+type Prefab struct {
+	Name   string           `json:"Name"`
+	Widget *NFWidget.Widget `json:"Widget,omitempty"`
+	Layout *NFLayout.Layout `json:"Layout,omitempty"`
+}
+
+// New creates a prefab of a widget subtree
+func New(name string, widget *NFWidget.Widget) *Prefab {
+	return &Prefab{Name: name, Widget: widget}
+}
+
+// NewLayout creates a prefab of a layout subtree
+func NewLayout(name string, layout *NFLayout.Layout) *Prefab {
+	return &Prefab{Name: name, Layout: layout}
+}
+
+// Validate checks that the prefab can be saved and instanced, the prefabs it instances are checked with ValidateReferences
+func (p *Prefab) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return NFError.NewErrCriticalSceneValidation("prefab has no name")
+	}
+	if (p.Widget == nil) == (p.Layout == nil) {
+		return NFError.NewErrCriticalSceneValidation("prefab " + p.Name + " must have either a Widget or a Layout")
+	}
+	var fullErr error
+	if p.Widget != nil {
+		fullErr = errors.Join(fullErr, p.Widget.Validate())
+	} else {
+		fullErr = errors.Join(fullErr, p.Layout.Validate())
+	}
+	return errors.Join(fullErr, ValidateReferences(p.widgets()...))
+}
+
+// widgets returns the top widgets of the prefab
+func (p *Prefab) widgets() []*NFWidget.Widget {
+	if p.Widget != nil {
+		return []*NFWidget.Widget{p.Widget}
+	}
+	if p.Layout != nil {
+		return p.Layout.Children
+	}
+	return nil
+}
+
+// args returns the args of the root widget or layout, creating them if it has none
+func (p *Prefab) args() *NFData.NFInterfaceMap {
+	if p.Widget != nil {
+		if p.Widget.Args == nil {
+			p.Widget.Args = NFData.NewNFInterfaceMap()
+		}
+		return p.Widget.Args
+	}
+	if p.Layout.Args == nil {
+		p.Layout.Args = NFData.NewNFInterfaceMap()
+	}
+	return p.Layout.Args
+}
+
+// References returns the names of the prefabs instanced inside the prefab
+func (p *Prefab) References() []string {
+	names := make([]string, 0)
+	walkWidgets(p.widgets(), func(w *NFWidget.Widget) {
+		if name, ok := prefabName(w); ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	})
+	return names
+}
+
+// Override merges the args in to every widget in the prefab with the name, it returns NFError.ErrBrokenReference if there is none
+func (p *Prefab) Override(name string, args *NFData.NFInterfaceMap) error {
+	found := false
+	walkWidgets(p.widgets(), func(w *NFWidget.Widget) {
+		if w.GetName() != name {
+			return
+		}
+		found = true
+		if w.Args == nil {
+			w.Args = NFData.NewNFInterfaceMap()
+		}
+		w.Args.Merge(args.Copy().(*NFData.NFInterfaceMap))
+	})
+	if !found {
+		return NFError.NewErrBrokenReference("widget in prefab "+p.Name, name)
+	}
+	return nil
+}
+
+// Parse parses the root widget or layout of the prefab
+func (p *Prefab) Parse(window fyne.Window) (fyne.CanvasObject, error) {
+	if p.Widget != nil {
+		return p.Widget.Parse(window)
+	}
+	if p.Layout != nil {
+		return p.Layout.Parse(window)
+	}
+	return nil, NFError.NewErrCriticalSceneValidation("prefab " + p.Name + " must have either a Widget or a Layout")
+}
+
+// Save writes the prefab to dir/Name.NFPrefab
+func (p *Prefab) Save(dir string) error {
+	err := p.Validate()
+	if err != nil && !errors.Is(err, NFError.ErrSceneValidation) && !errors.Is(err, NFError.ErrBrokenReference) {
+		return err
+	}
+	if filepath.Ext(dir) != "" {
+		dir = filepath.Dir(dir)
+	}
+	path := filepath.Join(dir, p.Name+Extension)
+	jsonBytes, err := json.MarshalIndent(p, "", "\t")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, jsonBytes, 0755)
+}
+
+// Load loads a prefab from a file on disk, this is used by the editor, games should use Get
+func Load(path string) (*Prefab, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decode(path, data)
+}
+
+func decode(path string, data []byte) (*Prefab, error) {
+	p := &Prefab{}
+	err := json.Unmarshal(data, p)
+	if err != nil {
+		return nil, NFError.NewErrFileGet(path, err.Error())
+	}
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(path), Extension)
+	}
+	if (p.Widget == nil) == (p.Layout == nil) {
+		return nil, NFError.NewErrFileGet(path, "a prefab must have either a Widget or a Layout")
+	}
+	return p, nil
+}
+
+// Register registers a prefab with the PrefabMap
+func Register(name, path string) error {
+	path = filepath.Clean(path)
+	if !fs.ValidPath(path) {
+		return errors.New("invalid path")
+	}
+	if _, ok := PrefabMap[name]; ok {
+		return NFError.NewErrKeyAlreadyExists(name)
+	}
+	PrefabMap[name] = path
+	return nil
+}
+
+// RegisterAll registers all prefabs by walking both the embedded and local filesystems
+//
+// Like RegisterAll for scenes this should only be called once at the start of the program
+func RegisterAll(path string) error {
+	return NFFS.Walk(path, NFFS.NewConfiguration(true), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, Extension) {
+			return nil
+		}
+		name := strings.TrimSuffix(d.Name(), Extension)
+		if oldPath, ok := PrefabMap[name]; ok {
+			log.Println("Prefab already registered: ", name, " at ", oldPath)
+			log.Println("Make sure prefabs have unique names")
+		} else {
+			PrefabMap[name] = path
+		}
+		return nil
+	})
+}
+
+// GetNames returns the names of all registered prefabs in order
+func GetNames() []string {
+	names := make([]string, 0, len(PrefabMap))
+	for name := range PrefabMap {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Get returns a new copy of the registered prefab with the name, prefab files are only read from the filesystem once.
+// It returns NFError.ErrBrokenReference if no prefab has the name
+func Get(name string, config ...NFFS.Configuration) (*Prefab, error) {
+	path, ok := PrefabMap[name]
+	if !ok {
+		return nil, NFError.NewErrBrokenReference("prefab", name)
+	}
+	cacheLock.Lock()
+	data, ok := cache[name]
+	cacheLock.Unlock()
+	if !ok {
+		if len(config) == 0 {
+			config = append(config, NFFS.NewConfiguration(true))
+		}
+		file, err := NFFS.Open(path, config[0])
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		data, err = io.ReadAll(file)
+		if err != nil {
+			return nil, err
+		}
+		cacheLock.Lock()
+		cache[name] = data
+		cacheLock.Unlock()
+	}
+	return decode(path, data)
+}
+
+// ClearCache forgets the prefab files read by Get so they are read again, this is used when the files change
+func ClearCache() {
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	cache = map[string][]byte{}
+}
+
+// Instance returns a copy of the prefab with the args of a Prefab widget applied to it, see WidgetType
+func Instance(name string, args *NFData.NFInterfaceMap) (*Prefab, error) {
+	err := checkCycle(name, nil)
+	if err != nil {
+		return nil, err
+	}
+	p, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if args == nil {
+		return p, nil
+	}
+	root := args.Copy().(*NFData.NFInterfaceMap)
+	overrides, _ := root.UnTypedGet("Overrides")
+	_ = root.DeleteMulti("Prefab", "Overrides")
+	p.args().Merge(root)
+	var fullErr error
+	for widgetName, value := range toMap(overrides) {
+		widgetArgs := toArgs(value)
+		if widgetArgs == nil {
+			fullErr = errors.Join(fullErr, NFError.NewErrInvalidArgument("Overrides", "the overrides for "+widgetName+" must be args"))
+			continue
+		}
+		fullErr = errors.Join(fullErr, p.Override(widgetName, widgetArgs))
+	}
+	return p, fullErr
+}
+
+// ValidateReferences checks that the prefabs instanced by the widgets and their children are registered,
+// do not instance themselves and have the widgets named in their overrides
+func ValidateReferences(widgets ...*NFWidget.Widget) error {
+	var fullErr error
+	walkWidgets(widgets, func(w *NFWidget.Widget) {
+		name, ok := prefabName(w)
+		if !ok {
+			return
+		}
+		err := checkCycle(name, nil)
+		if err != nil {
+			fullErr = errors.Join(fullErr, err)
+			return
+		}
+		overrides, _ := w.Args.UnTypedGet("Overrides")
+		if len(toMap(overrides)) == 0 {
+			return
+		}
+		p, err := Get(name)
+		if err != nil {
+			fullErr = errors.Join(fullErr, err)
+			return
+		}
+		for widgetName := range toMap(overrides) {
+			found := false
+			walkWidgets(p.widgets(), func(inner *NFWidget.Widget) {
+				found = found || inner.GetName() == widgetName
+			})
+			if !found {
+				fullErr = errors.Join(fullErr, NFError.NewErrBrokenReference("widget in prefab "+name, widgetName))
+			}
+		}
+	})
+	return fullErr
+}
+
+// checkCycle returns an error if the prefab or one it instances is missing or instances itself,
+// chain is the prefabs that instance it
+func checkCycle(name string, chain []string) error {
+	if slices.Contains(chain, name) {
+		return NFError.NewErrSceneValidation("prefab " + name + " instances itself through " + strings.Join(chain, " > "))
+	}
+	p, err := Get(name)
+	if err != nil {
+		return err
+	}
+	chain = append(slices.Clip(chain), name)
+	for _, reference := range p.References() {
+		err = checkCycle(reference, chain)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// widgetHandler parses a Prefab widget by instancing the prefab it names
+func widgetHandler(window fyne.Window, args *NFData.NFInterfaceMap, _ *NFWidget.Widget) (fyne.CanvasObject, error) {
+	var name string
+	err := args.Get("Prefab", &name)
+	if err != nil {
+		return nil, err
+	}
+	p, err := Instance(name, args)
+	if err != nil {
+		if p == nil {
+			return nil, err
+		}
+		log.Println("Error applying the overrides of prefab ", name, ": ", err)
+	}
+	return p.Parse(window)
+}
+
+// prefabName returns the name of the prefab a Prefab widget instances
+func prefabName(w *NFWidget.Widget) (string, bool) {
+	if w.Type != WidgetType || w.Args == nil {
+		return "", false
+	}
+	value, ok := w.Args.UnTypedGet("Prefab")
+	if !ok {
+		return "", false
+	}
+	name, ok := value.(string)
+	return name, ok
+}
+
+// walkWidgets calls onWidget with every widget and all of their children
+func walkWidgets(widgets []*NFWidget.Widget, onWidget func(*NFWidget.Widget)) {
+	for _, w := range widgets {
+		if w == nil {
+			continue
+		}
+		onWidget(w)
+		walkWidgets(w.Children, onWidget)
+	}
+}
+
+// toMap returns the entries of a map arg in any of the forms it is decoded as
+func toMap(value interface{}) map[string]interface{} {
+	switch value := value.(type) {
+	case *NFData.NFInterfaceMap:
+		if value != nil {
+			return value.Copy().(*NFData.NFInterfaceMap).Data
+		}
+	case NFData.CustomMap:
+		return value
+	case map[string]interface{}:
+		return value
+	}
+	return nil
+}
+
+// toArgs returns a map arg as args, or nil if it is not a map
+func toArgs(value interface{}) *NFData.NFInterfaceMap {
+	if args, ok := value.(*NFData.NFInterfaceMap); ok {
+		return args
+	}
+	entries := toMap(value)
+	if entries == nil {
+		return nil
+	}
+	return NFData.NewNFInterfaceFromMap(entries)
+}
@@ -0,0 +1,109 @@
+package NFScene
+
+import (
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFAsset"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFFS"
+	"log"
+	"sync"
+)
+
+// DefaultSceneCacheSize is the number of scenes the scene cache holds until SetSceneCacheSize is called
+const DefaultSceneCacheSize = 16
+
+// sceneCache holds the scenes read by Get and Preload by name, the least recently used scenes are dropped first
+var sceneCache = NFAsset.NewLRU[string, *cachedScene](DefaultSceneCacheSize)
+
+// cachedScene is the file of a scene with a spare copy of it that has already been decoded.
+//
+// Scenes are changed while they are shown, their Args become the scene variables, so every Get needs its own copy.
+// Once the spare is taken the next one is decoded in the background, so only the first Get decodes on the UI path
+type cachedScene struct {
+	name      string
+	data      []byte
+	spare     *Scene
+	decoding  bool
+	assets    []string
+	reachable []string
+	analysed  bool
+	lock      sync.Mutex
+}
+
+// cacheScene returns the cached scene for the name, reading it in to the cache if it is not there
+func cacheScene(name string) (*cachedScene, error) {
+	if cached, ok := sceneCache.Get(name); ok {
+		return cached, nil
+	}
+	data, err := readScene(name, NFFS.NewConfiguration(true))
+	if err != nil {
+		return nil, err
+	}
+	cached := &cachedScene{name: name, data: data}
+	sceneCache.Add(name, cached)
+	return cached, nil
+}
+
+// decode decodes a new copy of the scene, the first copy is used to find the scene's assets and reachable scenes
+func (c *cachedScene) decode() (*Scene, error) {
+	scene, err := decodeScene(c.data)
+	if err != nil {
+		return nil, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.analysed {
+		c.assets, c.reachable, c.analysed = scene.Assets(), scene.Reachable(), true
+	}
+	return scene, nil
+}
+
+// take returns the spare copy of the scene, or decodes one if there is none, and starts decoding the next spare
+func (c *cachedScene) take() (*Scene, error) {
+	c.lock.Lock()
+	scene := c.spare
+	c.spare = nil
+	c.lock.Unlock()
+	var err error
+	if scene == nil {
+		scene, err = c.decode()
+		if err != nil {
+			return nil, err
+		}
+	}
+	go c.fill()
+	return scene, nil
+}
+
+// fill decodes the spare copy of the scene if there is none and none is being decoded
+func (c *cachedScene) fill() {
+	c.lock.Lock()
+	if c.spare != nil || c.decoding {
+		c.lock.Unlock()
+		return
+	}
+	c.decoding = true
+	c.lock.Unlock()
+	scene, err := c.decode()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.decoding = false
+	if err != nil {
+		log.Println("Error decoding scene ", c.name, ": ", err)
+		return
+	}
+	c.spare = scene
+}
+
+// SetSceneCacheSize changes how many scenes are kept in the scene cache, the least recently used scenes are dropped first
+func SetSceneCacheSize(size int) {
+	sceneCache.Resize(size)
+}
+
+// ClearSceneCache drops every cached scene so they are read from the filesystem again, such as after they were edited
+func ClearSceneCache() {
+	sceneCache.Clear()
+}
+
+// GetCachedScenes returns the names of the cached scenes from the most to the least recently used
+func GetCachedScenes() []string {
+	return sceneCache.Keys()
+}
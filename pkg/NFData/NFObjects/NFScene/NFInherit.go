@@ -0,0 +1,145 @@
+package NFScene
+
+import (
+	"encoding/json"
+	"errors"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFFS"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFPrefab"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget"
+	"slices"
+	"strings"
+)
+
+// SlotType is the type of the widget that marks a named slot in a base scene.
+//
+// A scene that Extends the base uses the base's layout, and each of the top widgets of its own layout
+// replaces the slot with the same name, slots that are not replaced show their children.
+// The functions, args and overlays of the base come first and the scene's own are added over them
+const SlotType = "Slot"
+
+func init() {
+	slot := NFWidget.Widget{
+		Type:         SlotType,
+		RequiredArgs: NFData.NewNFInterfaceMap(),
+		OptionalArgs: NFData.NewNFInterfaceMap(),
+	}
+	slot.Register(slotHandler)
+}
+
+// slotHandler parses a slot that no scene has replaced by stacking its children
+func slotHandler(window fyne.Window, _ *NFData.NFInterfaceMap, w *NFWidget.Widget) (fyne.CanvasObject, error) {
+	var widgetError error
+	stack := container.NewStack()
+	for _, child := range w.Children {
+		parsedChild, err := child.Parse(window)
+		if err != nil {
+			widgetError = errors.Join(widgetError, err)
+			continue
+		}
+		stack.Add(parsedChild)
+	}
+	return stack, widgetError
+}
+
+// inherit resolves the base scene the scene extends in to the scene, see SlotType.
+// chain is the scenes that extend the scene
+func (scene *Scene) inherit(chain []string) error {
+	if scene.Extends == "" {
+		return nil
+	}
+	base, err := baseScene(scene.Extends, append(slices.Clip(chain), scene.Name))
+	if err != nil {
+		return err
+	}
+	fills := make(map[string]*NFWidget.Widget)
+	if scene.Layout != nil {
+		for _, child := range scene.Layout.Children {
+			fills[child.GetName()] = child
+		}
+	}
+	base.Layout.Children = fillSlots(base.Layout.Children, fills)
+	scene.Layout = base.Layout
+	scene.Functions = append(base.Functions, scene.Functions...)
+	scene.Overlays = append(base.Overlays, scene.Overlays...)
+	if base.Args == nil {
+		base.Args = NFData.NewNFInterfaceMap()
+	}
+	scene.Args = base.Args.Merge(scene.Args)
+	return nil
+}
+
+// baseScene reads the scene with the name and resolves what it extends in turn, chain is the scenes that extend it
+func baseScene(name string, chain []string) (*Scene, error) {
+	if slices.Contains(chain, name) {
+		return nil, NFError.NewErrSceneValidation("scene " + name + " extends itself through " + strings.Join(chain, " > "))
+	}
+	if _, ok := SceneMap[name]; !ok {
+		return nil, NFError.NewErrBrokenReference("scene", name)
+	}
+	data, err := readScene(name, NFFS.NewConfiguration(true))
+	if err != nil {
+		return nil, err
+	}
+	base := &Scene{}
+	err = json.Unmarshal(data, base)
+	if err != nil {
+		return nil, err
+	}
+	if base.Layout == nil {
+		return nil, NFError.NewErrSceneValidation("base scene " + name + " has no layout")
+	}
+	return base, base.inherit(chain)
+}
+
+// fillSlots replaces the slots in the widgets and their children with the fill of the same name
+func fillSlots(widgets []*NFWidget.Widget, fills map[string]*NFWidget.Widget) []*NFWidget.Widget {
+	for i, w := range widgets {
+		if fill, ok := fills[w.GetName()]; ok && w.Type == SlotType {
+			widgets[i] = fill
+			continue
+		}
+		w.Children = fillSlots(w.Children, fills)
+	}
+	return widgets
+}
+
+// slotNames returns the names of the slots in the widgets and their children
+func slotNames(widgets []*NFWidget.Widget, names []string) []string {
+	for _, w := range widgets {
+		if w.Type == SlotType {
+			names = append(names, w.GetName())
+		}
+		names = slotNames(w.Children, names)
+	}
+	return names
+}
+
+// validateReferences checks that the prefabs the scene instances and the base scene it extends exist,
+// and that the base has a slot for each top widget of the scene's layout
+func (scene *Scene) validateReferences() error {
+	widgets := slices.Clone(scene.Layout.Children)
+	for _, overlay := range scene.Overlays {
+		if overlay.layout != nil {
+			widgets = append(widgets, overlay.layout.Children...)
+		}
+	}
+	refError := NFPrefab.ValidateReferences(widgets...)
+	if scene.Extends == "" {
+		return refError
+	}
+	base, err := baseScene(scene.Extends, []string{scene.Name})
+	if err != nil {
+		return errors.Join(refError, err)
+	}
+	slots := slotNames(base.Layout.Children, nil)
+	for _, child := range scene.Layout.Children {
+		if !slices.Contains(slots, child.GetName()) {
+			refError = errors.Join(refError, NFError.NewErrBrokenReference("slot in scene "+scene.Extends, child.GetName()))
+		}
+	}
+	return refError
+}
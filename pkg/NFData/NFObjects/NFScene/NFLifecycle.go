@@ -0,0 +1,57 @@
+package NFScene
+
+import (
+	"fyne.io/fyne/v2"
+	"log"
+)
+
+// The lifecycle actions are run on the scene's Functions by the runtime, so scenes can set variables,
+// start music or autosave without code. Every function with the action is run, in order of Priority.
+//
+// When a scene is navigated to with Show, ChangeScene, PushScene or PopScene the order is:
+//  1. ActionOnExit on the scene being left, while its scene data is still active
+//  2. ActionOnLoad on the new scene, once its scene data is active
+//  3. ActionOnEnter on the new scene
+//  4. the new scene is parsed with Parse, which runs no actions, so its widgets see the variables set by the actions before it
+//  5. the transition to the new scene is played
+//
+// If the new scene fails to parse the scene being left stays shown and its scene data is made active again,
+// the actions that already ran are not undone.
+// When a save is resumed with ResumeSave ActionOnEnter is not run, instead the state in the save is restored
+// and ActionOnResume is run in its place. Rollback runs ActionOnExit and ActionOnLoad only, as the player is
+// returning to a line rather than arriving at the scene
+const (
+	// ActionOnLoad is run every time the scene is loaded in to the active scene data before it is parsed, including when resuming
+	ActionOnLoad = "OnLoad"
+	// ActionOnEnter is run when the player navigates to the scene, before it is parsed, it is not run when resuming a save
+	ActionOnEnter = "OnEnter"
+	// ActionOnExit is run on the scene that is being left, just before the next scene is loaded
+	ActionOnExit = "OnExit"
+	// ActionOnResume is run when the scene is shown by resuming a save, after the save's state has been restored and before it is parsed
+	ActionOnResume = "OnResume"
+)
+
+// activeScene is the scene that was last loaded by the navigation functions, it is the scene ActionOnExit is run on
+var activeScene *Scene
+
+// GetLifecycleActions returns the lifecycle actions in the order they can be run
+func GetLifecycleActions() []string {
+	return []string{ActionOnExit, ActionOnLoad, ActionOnEnter, ActionOnResume}
+}
+
+// RunLifecycleAction runs every function of the scene with the lifecycle action, scenes without any are skipped.
+// Errors are logged so a broken function never stops the scene from being shown, and are returned joined
+func (scene *Scene) RunLifecycleAction(action string, window fyne.Window) error {
+	if scene == nil {
+		return nil
+	}
+	//No values are passed as Run merges them in to the function's own args
+	count, _, err := scene.RunAllActions(action, window, nil)
+	if count == 0 {
+		return nil
+	}
+	if err != nil {
+		log.Println("Error running ", action, " on scene ", scene.Name, ": ", err)
+	}
+	return err
+}
@@ -0,0 +1,292 @@
+package NFScene
+
+import (
+	"fyne.io/fyne/v2"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFAsset"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+	"image"
+	"log"
+	"sync"
+)
+
+// ActiveStack is the SceneStack that is currently set as the window content by the navigation functions
+var ActiveStack *SceneStack
+
+// sceneLeft is closed when the scene that is being shown is left, see SceneLeft
+var sceneLeft = make(chan struct{})
+var sceneLeftLock sync.Mutex
+
+// SceneLeft returns a channel that is closed once the scene being parsed or shown is replaced by another scene,
+// widgets that run timers, such as a timed ChoiceMenu, use it to stop when their scene is left.
+// Unlike comparing the window content, it is not fooled by the loading bar or a transition standing in for the scene
+func SceneLeft() <-chan struct{} {
+	sceneLeftLock.Lock()
+	defer sceneLeftLock.Unlock()
+	return sceneLeft
+}
+
+// swapSceneLeft makes left the channel SceneLeft returns and returns the one it replaced
+func swapSceneLeft(left chan struct{}) chan struct{} {
+	sceneLeftLock.Lock()
+	defer sceneLeftLock.Unlock()
+	previous := sceneLeft
+	sceneLeft = left
+	return previous
+}
+
+// navigationStack holds the names of the scenes that were left with PushScene, so PopScene can return to them
+var navigationStack []string
+
+// Current returns the name of the scene that is currently loaded in to the active scene data
+func Current() string {
+	if NFData.ActiveSceneData == nil {
+		return ""
+	}
+	return NFData.ActiveSceneData.GetSceneName()
+}
+
+// Show gets the scene by name, loads and parses it, and sets it as the content of the window
+//
+// The active save is updated with the new scene and the current navigation stack,
+// and the scene state left by the previous scene is cleared so the new scene starts fresh.
+// Once the scene is shown it is autosaved if NFConfig.Game.Autosave has OnSceneChange set.
+// The lifecycle actions of the scene being left and the new scene are run in the order documented on ActionOnLoad,
+// the new scene is loaded and its actions are run before it is parsed so its widgets see the variables they set.
+//
+// The first transition passed is played from the current content, otherwise the transition in the scene's args is,
+// see ParseTransition, and if it has none the DefaultTransition.
+//
+// A scene that uses HeavySceneAssets or more assets that have not been preloaded shows a loading bar while they load.
+// Once the scene is shown the scenes named in its args["Preload"] are preloaded, and if args["PreloadReachable"]
+// is true so is every scene it can reach, see Preload
+func Show(window fyne.Window, name string, transition ...TransitionOptions) (*SceneStack, error) {
+	return show(window, name, false, nil, nil, transition)
+}
+
+// show is Show with the option to keep the scene state in the active save, which is used when resuming a save.
+// When not resuming the scene starts with the state, which is nil unless it is a scene returned to with PopScene.
+// When resuming, restore is run once the scene is loaded in place of ActionOnEnter, its error is returned once the scene is shown
+func show(window fyne.Window, name string, resume bool, state map[string]int, restore func() error, transition []TransitionOptions) (*SceneStack, error) {
+	scene, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	options := DefaultTransition
+	if len(transition) > 0 {
+		options = transition[0]
+	} else if sceneOptions, ok, err := ParseTransition(scene.Args); err != nil {
+		log.Println("Error reading the transition of scene ", name, ": ", err)
+	} else if ok {
+		options = sceneOptions
+	}
+	//The save is moved to the scene before it is parsed so that widgets such as NarrativeBox record their lines against it
+	save := NFSave.Active
+	var previousScene string
+	var previousStack []string
+	var previousState map[string]int
+	if save != nil {
+		previousScene, previousStack, previousState = save.GetScene(), save.GetSceneStack(), save.SceneState
+		if !resume {
+			save.ClearSceneState()
+			for key, value := range state {
+				save.SetSceneState(key, value)
+			}
+		}
+		save.SetScene(name)
+		save.SetSceneStack(GetSceneStack())
+	}
+	//The old scene is left and the new scene loaded before it is parsed, so the text its widgets evaluate
+	//sees the new scene's variables and whatever its OnLoad and OnEnter functions set
+	leaving, previousData := activeScene, NFData.ActiveSceneData
+	_ = leaving.RunLifecycleAction(ActionOnExit, window)
+	activeScene = scene
+	scene.load(window)
+	var restoreErr error
+	if restore != nil {
+		restoreErr = restore()
+	} else if !resume {
+		_ = scene.RunLifecycleAction(ActionOnEnter, window)
+	}
+	if activeScene != scene {
+		//OnLoad or OnEnter moved on to another scene which is already being shown
+		return ActiveStack, restoreErr
+	}
+	//Scenes with many assets that are not preloaded show a loading bar while they load instead of freezing
+	previousContent := window.Content()
+	if assets := NFAsset.Uncached(scene.Assets()); len(assets) >= HeavySceneAssets {
+		loadAssets(window, name, assets)
+	}
+	//The state the scene's widgets keep, such as stages, replaces that of the old scene once it is shown
+	swap := BeginSceneSwap()
+	left := make(chan struct{})
+	previousLeft := swapSceneLeft(left)
+	stack, err := scene.Parse(window)
+	if err != nil {
+		swap.Discard()
+		//The widgets of the scene that failed stop, the scene that is still shown keeps running with its own data
+		swapSceneLeft(previousLeft)
+		close(left)
+		activeScene, NFData.ActiveSceneData = leaving, previousData
+		if window.Content() != previousContent && previousContent != nil {
+			window.SetContent(previousContent)
+		}
+		if save != nil {
+			save.SetScene(previousScene)
+			save.SetSceneStack(previousStack)
+			save.SceneState = previousState
+		}
+		return nil, err
+	}
+	ActiveStack = stack
+	swap.Commit()
+	close(previousLeft)
+	//The autosave waits for the transition so its thumbnail shows the new scene
+	present(window, stack, options, func() {
+		//The lines the last scene marked read are written now instead of waiting for NFSave.PersistentSaveDelay
+		persistent := NFSave.Persistent
+		go func() {
+			err := persistent.Flush()
+			if err != nil {
+				log.Println("Error saving persistent data: ", err)
+			}
+		}()
+		if !resume {
+			Autosave(window, NFSave.AutosaveSceneChange)
+		}
+	})
+	preloadHints(scene)
+	return stack, restoreErr
+}
+
+// Autosave runs NFSave.Autosave for the trigger with a thumbnail of the window, errors are logged so they never stop the game
+func Autosave(window fyne.Window, trigger string) {
+	if NFSave.Active == nil {
+		return
+	}
+	var thumbnail image.Image
+	if window != nil {
+		thumbnail = window.Canvas().Capture()
+	}
+	_, err := NFSave.Autosave(trigger, thumbnail)
+	if err != nil {
+		log.Println("Error autosaving: ", err)
+	}
+}
+
+// ChangeScene replaces the current scene with the named scene without touching the navigation stack
+func ChangeScene(window fyne.Window, name string, transition ...TransitionOptions) error {
+	log.Println("Changing scene to: ", name)
+	_, err := Show(window, name, transition...)
+	return err
+}
+
+// PushScene remembers the current scene on the navigation stack before changing to the named scene,
+// so that PopScene can later return to the caller. The caller's scene state is kept in the active save
+// so widgets such as NarrativeBox continue where they were when it is returned to
+func PushScene(window fyne.Window, name string, transition ...TransitionOptions) error {
+	current := Current()
+	save := NFSave.Active
+	if current != "" {
+		navigationStack = append(navigationStack, current)
+		if save != nil {
+			save.PushSceneState(save.SceneState)
+		}
+	}
+	log.Println("Pushing scene: ", name, " returning to: ", current)
+	_, err := Show(window, name, transition...)
+	if err != nil && current != "" {
+		//The scene never changed so the caller should not be left on the stack
+		navigationStack = navigationStack[:len(navigationStack)-1]
+		if save != nil {
+			save.PopSceneState()
+		}
+	}
+	return err
+}
+
+// PopScene returns to the last scene pushed on to the navigation stack and returns its name,
+// the scene starts with the scene state it had when it was left so it continues where it was
+func PopScene(window fyne.Window, transition ...TransitionOptions) (string, error) {
+	if len(navigationStack) == 0 {
+		return "", NFError.NewErrNotFound("no scene to return to, the navigation stack is empty")
+	}
+	name := navigationStack[len(navigationStack)-1]
+	navigationStack = navigationStack[:len(navigationStack)-1]
+	save := NFSave.Active
+	var state map[string]int
+	if save != nil {
+		state = save.PopSceneState()
+	}
+	log.Println("Popping scene, returning to: ", name)
+	_, err := show(window, name, false, state, nil, transition)
+	if err != nil {
+		//Keep the scene on the stack so that the return can be retried
+		navigationStack = append(navigationStack, name)
+		if save != nil {
+			save.PushSceneState(state)
+		}
+		return "", err
+	}
+	return name, nil
+}
+
+// GetSceneStack returns a copy of the navigation stack with the most recently pushed scene last
+func GetSceneStack() []string {
+	stack := make([]string, len(navigationStack))
+	copy(stack, navigationStack)
+	return stack
+}
+
+// SetSceneStack replaces the navigation stack, this is used when restoring a save
+func SetSceneStack(stack []string) {
+	navigationStack = make([]string, len(stack))
+	copy(navigationStack, stack)
+}
+
+// ClearSceneStack removes all scenes from the navigation stack, along with their scene state in the active save
+func ClearSceneStack() {
+	navigationStack = nil
+	if NFSave.Active != nil {
+		NFSave.Active.ClearSceneStates()
+	}
+}
+
+// ResumeSave restores the navigation stack from the active save and shows the scene it was saved on,
+// keeping the scene state so widgets such as NarrativeBox continue where the player left off.
+// Once the scene is loaded the registered NFSave.StateProviders are restored from the save's sections,
+// and then the scene's ActionOnResume functions are run, both before the scene is parsed
+func ResumeSave(window fyne.Window) error {
+	return resume(window, func() error {
+		//The providers are restored after the scene is loaded so the scene's own variables are not replaced by its defaults
+		err := NFSave.Active.RestoreState()
+		_ = activeScene.RunLifecycleAction(ActionOnResume, window)
+		return err
+	})
+}
+
+// resume shows the scene of the active save with restore run once it is loaded, Rollback restores the state kept with
+// the line being returned to rather than the sections of the save, which are from when it was written
+func resume(window fyne.Window, restore func() error) error {
+	if NFSave.Active == nil {
+		return NFError.NewErrNotFound("no active save to resume")
+	}
+	SetSceneStack(NFSave.Active.GetSceneStack())
+	log.Println("Resuming save on scene: ", NFSave.Active.GetScene())
+	_, err := show(window, NFSave.Active.GetScene(), true, nil, restore, nil)
+	return err
+}
+
+// Rollback restores the active save to the backlog entry at index and shows the scene the entry's line was shown on
+func Rollback(window fyne.Window, index int) error {
+	if NFSave.Active == nil {
+		return NFError.NewErrNotFound("no active save to roll back")
+	}
+	entry, err := NFSave.Active.Rollback(index)
+	if err != nil {
+		return err
+	}
+	log.Println("Rolling back to line: ", entry.Text, " on scene: ", entry.Scene)
+	return resume(window, entry.RestoreRollbackState)
+}
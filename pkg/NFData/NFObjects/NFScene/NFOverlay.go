@@ -1,17 +1,142 @@
 package NFScene
 
 import (
+	"cmp"
+	"encoding/json"
 	"errors"
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFFS"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFLayout"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+	"image/color"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 )
 
+// OverlayExtension is the file extension used for overlay files
+const OverlayExtension = ".NFOverlay"
+
+// OverlayDirectory is where the overlays of a game are kept, relative to the game directory
+const OverlayDirectory = "data/overlays"
+
+// Overlays holds the overlays that can be drawn over every scene by name,
+// they are added with AddOverlay or loaded from .NFOverlay files with RegisterAllOverlays
 var Overlays = make(map[string]*NFOverlay)
 
+// OverlayStateSection is the section of a save that holds which overlays are visible
+const OverlayStateSection = "Overlays"
+
+func init() {
+	_ = NFSave.RegisterStateProvider(OverlayStateSection, NFSave.StateFuncs{Save: saveOverlayState, Load: loadOverlayState})
+}
+
+// saveOverlayState returns the visibility of every overlay by name, including the overlays of the scene being shown
+func saveOverlayState() (interface{}, error) {
+	visible := make(map[string]bool, len(Overlays))
+	for name, overlay := range Overlays {
+		visible[name] = overlay.visible
+	}
+	if ActiveStack != nil {
+		for _, overlay := range ActiveStack.overlays {
+			visible[overlay.name] = overlay.visible
+		}
+	}
+	return visible, nil
+}
+
+// loadOverlayState sets the visibility of the overlays in the save, ResumeSave refreshes the active stack afterwards
+func loadOverlayState(data json.RawMessage) error {
+	if data == nil {
+		return nil
+	}
+	visible := map[string]bool{}
+	err := json.Unmarshal(data, &visible)
+	if err != nil {
+		return err
+	}
+	for name, overlay := range Overlays {
+		if shown, ok := visible[name]; ok {
+			overlay.visible = shown
+		}
+	}
+	//The overlays of the active scene are the ones its stack is parsed with, a resumed scene is loaded before it is parsed
+	if activeScene != nil {
+		for _, overlay := range activeScene.Overlays {
+			if shown, ok := visible[overlay.name]; ok {
+				overlay.visible = shown
+			}
+		}
+	}
+	return nil
+}
+
+// NFOverlay is a layout drawn over scenes, such as a HUD or a pause menu.
+//
+// Overlays with a higher z-index are drawn over those with a lower one, a modal overlay blocks input to everything
+// under it, and the allowed and denied scenes limit which scenes it is drawn on
 type NFOverlay struct {
-	name    string
-	layout  *NFLayout.Layout
-	visible bool
+	name        string
+	layout      *NFLayout.Layout
+	visible     bool
+	zIndex      int
+	modal       bool
+	allowScenes []string
+	denyScenes  []string
+}
+
+// overlayJSON is how an NFOverlay is stored in .NFOverlay files and in the Overlays of a scene
+type overlayJSON struct {
+	Name   string           `json:"Name"`
+	Layout *NFLayout.Layout `json:"Layout"`
+	// Visible is whether the overlay is shown before anything shows or hides it
+	Visible bool `json:"Visible"`
+	ZIndex  int  `json:"ZIndex"`
+	Modal   bool `json:"Modal"`
+	// AllowScenes is the only scenes the overlay is drawn on, if it is empty it is drawn on every scene
+	AllowScenes []string `json:"AllowScenes,omitempty"`
+	// DenyScenes is the scenes the overlay is never drawn on
+	DenyScenes []string `json:"DenyScenes,omitempty"`
+}
+
+func (o *NFOverlay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(overlayJSON{
+		Name:        o.name,
+		Layout:      o.layout,
+		Visible:     o.visible,
+		ZIndex:      o.zIndex,
+		Modal:       o.modal,
+		AllowScenes: o.allowScenes,
+		DenyScenes:  o.denyScenes,
+	})
+}
+
+func (o *NFOverlay) UnmarshalJSON(data []byte) error {
+	decoded := overlayJSON{}
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		return err
+	}
+	if decoded.Layout == nil {
+		return NFError.NewErrInvalidArgument("Layout", "overlay "+decoded.Name+" has no layout")
+	}
+	*o = NFOverlay{
+		name:        decoded.Name,
+		layout:      decoded.Layout,
+		visible:     decoded.Visible,
+		zIndex:      decoded.ZIndex,
+		modal:       decoded.Modal,
+		allowScenes: decoded.AllowScenes,
+		denyScenes:  decoded.DenyScenes,
+	}
+	return nil
 }
 
 func (o *NFOverlay) Name() string {
@@ -34,6 +159,50 @@ func (o *NFOverlay) Visible() bool {
 	return o.visible
 }
 
+// ZIndex returns the order the overlay is drawn in, overlays with a higher z-index are drawn over those with a lower one
+func (o *NFOverlay) ZIndex() int {
+	return o.zIndex
+}
+
+func (o *NFOverlay) SetZIndex(zIndex int) {
+	o.zIndex = zIndex
+}
+
+// Modal returns true if the overlay blocks input to the scene and the overlays under it while it is shown
+func (o *NFOverlay) Modal() bool {
+	return o.modal
+}
+
+func (o *NFOverlay) SetModal(modal bool) {
+	o.modal = modal
+}
+
+// AllowScenes returns the only scenes the overlay is drawn on, if it is empty it is drawn on every scene that is not denied
+func (o *NFOverlay) AllowScenes() []string {
+	return o.allowScenes
+}
+
+func (o *NFOverlay) SetAllowScenes(scenes ...string) {
+	o.allowScenes = scenes
+}
+
+// DenyScenes returns the scenes the overlay is never drawn on
+func (o *NFOverlay) DenyScenes() []string {
+	return o.denyScenes
+}
+
+func (o *NFOverlay) SetDenyScenes(scenes ...string) {
+	o.denyScenes = scenes
+}
+
+// AllowedOn returns true if the overlay can be drawn on the named scene
+func (o *NFOverlay) AllowedOn(scene string) bool {
+	if slices.Contains(o.denyScenes, scene) {
+		return false
+	}
+	return len(o.allowScenes) == 0 || slices.Contains(o.allowScenes, scene)
+}
+
 func (o *NFOverlay) SetVisible(visible bool, window fyne.Window, updateScenes ...*SceneStack) {
 	o.visible = visible
 	for _, scene := range updateScenes {
@@ -55,6 +224,38 @@ func (o *NFOverlay) Show(window fyne.Window, updateScenes ...*SceneStack) {
 	}
 }
 
+// Validate checks that the overlay has a name and a valid layout
+func (o *NFOverlay) Validate() error {
+	if strings.TrimSpace(o.name) == "" {
+		return NFError.NewErrCriticalSceneValidation("overlay has no name")
+	}
+	if o.layout == nil {
+		return NFError.NewErrCriticalSceneValidation("overlay " + o.name + " has no layout")
+	}
+	return o.layout.Validate()
+}
+
+// Save writes the overlay to dir/Name.NFOverlay
+func (o *NFOverlay) Save(dir string) error {
+	err := o.Validate()
+	if err != nil && errors.Is(err, NFError.ErrCriticalSceneValidation) {
+		return err
+	}
+	if filepath.Ext(dir) != "" {
+		dir = filepath.Dir(dir)
+	}
+	path := filepath.Join(dir, o.name+OverlayExtension)
+	jsonBytes, err := json.MarshalIndent(o, "", "\t")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, jsonBytes, 0755)
+}
+
 func NewNFOverlay(name string, layout *NFLayout.Layout) *NFOverlay {
 	return &NFOverlay{
 		name:    name,
@@ -75,3 +276,156 @@ func AddOverlay(overlay ...*NFOverlay) error {
 	}
 	return nil
 }
+
+// LoadOverlay loads an overlay from a file on disk, this is used by the editor, games should use RegisterAllOverlays
+func LoadOverlay(path string) (*NFOverlay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeOverlay(path, data)
+}
+
+func decodeOverlay(path string, data []byte) (*NFOverlay, error) {
+	overlay := &NFOverlay{}
+	err := json.Unmarshal(data, overlay)
+	if err != nil {
+		return nil, NFError.NewErrFileGet(path, err.Error())
+	}
+	if overlay.name == "" {
+		overlay.name = strings.TrimSuffix(filepath.Base(path), OverlayExtension)
+	}
+	return overlay, nil
+}
+
+// RegisterAllOverlays loads every .NFOverlay file by walking both the embedded and local filesystems and adds them to Overlays
+//
+// Like RegisterAll for scenes this should only be called once at the start of the program
+func RegisterAllOverlays(path string) error {
+	var fullErr error
+	err := NFFS.Walk(path, NFFS.NewConfiguration(true), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, OverlayExtension) {
+			return nil
+		}
+		data, err := NFFS.ReadFile(path, NFFS.NewConfiguration(true))
+		if err != nil {
+			fullErr = errors.Join(fullErr, err)
+			return nil
+		}
+		overlay, err := decodeOverlay(path, data)
+		if err != nil {
+			fullErr = errors.Join(fullErr, err)
+			return nil
+		}
+		if _, ok := Overlays[overlay.name]; ok {
+			log.Println("Overlay already registered: ", overlay.name, " skipping ", path)
+			log.Println("Make sure overlays have unique names for easy management")
+			return nil
+		}
+		Overlays[overlay.name] = overlay
+		return nil
+	})
+	return errors.Join(err, fullErr)
+}
+
+// GetOverlay returns the overlay by name, the overlays of the scene being shown are found before the global Overlays
+func GetOverlay(name string) (*NFOverlay, error) {
+	if ActiveStack != nil {
+		for _, overlay := range ActiveStack.overlays {
+			if overlay.name == name {
+				return overlay, nil
+			}
+		}
+	}
+	if overlay, ok := Overlays[name]; ok {
+		return overlay, nil
+	}
+	return nil, NFError.NewErrNotFound("overlay " + name)
+}
+
+// ShowOverlay shows the named overlay over the active scene
+func ShowOverlay(window fyne.Window, name string) error {
+	return setOverlayVisible(window, name, func(bool) bool { return true })
+}
+
+// HideOverlay hides the named overlay
+func HideOverlay(window fyne.Window, name string) error {
+	return setOverlayVisible(window, name, func(bool) bool { return false })
+}
+
+// ToggleOverlay shows the named overlay if it is hidden and hides it if it is shown, it returns whether it is now shown
+func ToggleOverlay(window fyne.Window, name string) (bool, error) {
+	var visible bool
+	err := setOverlayVisible(window, name, func(shown bool) bool {
+		visible = !shown
+		return visible
+	})
+	return visible, err
+}
+
+// setOverlayVisible sets the visibility of the named overlay from its current visibility and refreshes the active stack
+func setOverlayVisible(window fyne.Window, name string, visible func(bool) bool) error {
+	overlay, err := GetOverlay(name)
+	if err != nil {
+		return err
+	}
+	overlay.visible = visible(overlay.visible)
+	if ActiveStack != nil {
+		ActiveStack.RefreshOverlays(window)
+	}
+	return nil
+}
+
+// visibleOverlays returns the visible overlays that are allowed on the stack's scene, from the lowest z-index to the highest
+func (s *SceneStack) visibleOverlays() []*NFOverlay {
+	scene := s.scene
+	if scene == "" {
+		scene = Current()
+	}
+	visible := make([]*NFOverlay, 0)
+	for _, overlay := range Overlays {
+		if overlay.visible && overlay.AllowedOn(scene) {
+			visible = append(visible, overlay)
+		}
+	}
+	for _, overlay := range s.overlays {
+		if overlay.visible && overlay.AllowedOn(scene) {
+			visible = append(visible, overlay)
+		}
+	}
+	//Overlays with the same z-index are ordered by name so they are drawn the same way every time
+	slices.SortStableFunc(visible, func(a, b *NFOverlay) int {
+		return cmp.Or(cmp.Compare(a.zIndex, b.zIndex), cmp.Compare(a.name, b.name))
+	})
+	return visible
+}
+
+// inputBlocker is put under modal overlays, it takes the taps, scrolls and hovers so they do not reach anything under it
+type inputBlocker struct {
+	widget.BaseWidget
+}
+
+func newInputBlocker() *inputBlocker {
+	blocker := &inputBlocker{}
+	blocker.ExtendBaseWidget(blocker)
+	return blocker
+}
+
+func (b *inputBlocker) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(canvas.NewRectangle(color.Transparent))
+}
+
+func (b *inputBlocker) Tapped(*fyne.PointEvent)          {}
+func (b *inputBlocker) TappedSecondary(*fyne.PointEvent) {}
+func (b *inputBlocker) DoubleTapped(*fyne.PointEvent)    {}
+func (b *inputBlocker) Scrolled(*fyne.ScrollEvent)       {}
+func (b *inputBlocker) MouseIn(*desktop.MouseEvent)      {}
+func (b *inputBlocker) MouseMoved(*desktop.MouseEvent)   {}
+func (b *inputBlocker) MouseOut()                        {}
+func (b *inputBlocker) Dragged(*fyne.DragEvent)          {}
+func (b *inputBlocker) DragEnd()                         {}
+func (b *inputBlocker) MouseDown(*desktop.MouseEvent)    {}
+func (b *inputBlocker) MouseUp(*desktop.MouseEvent)      {}
@@ -0,0 +1,177 @@
+package NFScene
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFAsset"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFCharacter"
+	"log"
+	"slices"
+	"strconv"
+)
+
+// HeavySceneAssets is how many assets a scene must use that have not been preloaded for Show to put a
+// loading bar in the window while they are loaded, lighter scenes load their assets as they are drawn
+var HeavySceneAssets = 8
+
+// Assets returns the paths in the args of the scene and everything in it that have an NFAsset preloader,
+// along with the expression images of the characters in them
+func (scene *Scene) Assets() []string {
+	assets := make([]string, 0)
+	scene.walkArgs(func(value string) {
+		if NFAsset.Preloadable(value) && !slices.Contains(assets, value) {
+			assets = append(assets, value)
+		}
+	}, func(args map[string]interface{}) {
+		id, ok := args["Character"].(string)
+		if !ok || id == "" {
+			return
+		}
+		character, err := NFCharacter.Get(id)
+		if err != nil {
+			return
+		}
+		expression, _ := args["Expression"].(string)
+		path, err := character.Expression(expression)
+		if err == nil && path != "" && !slices.Contains(assets, path) {
+			assets = append(assets, path)
+		}
+	})
+	return assets
+}
+
+// Reachable returns the names of the registered scenes that are named in the args of the scene and everything in it,
+// such as the Scene of a ChangeScene function, so they can be preloaded before the player moves on to them
+func (scene *Scene) Reachable() []string {
+	reachable := make([]string, 0)
+	scene.walkArgs(func(value string) {
+		if _, ok := SceneMap[value]; ok && value != scene.Name && !slices.Contains(reachable, value) {
+			reachable = append(reachable, value)
+		}
+	}, nil)
+	return reachable
+}
+
+// walkArgs calls onString with every string in the args of the scene, its layout, overlays, widgets and functions,
+// and onMap with every map in them including the args themselves
+func (scene *Scene) walkArgs(onString func(string), onMap func(map[string]interface{})) {
+	objects, _ := scene.FetchAll()
+	for _, overlay := range scene.Overlays {
+		objects[scene.GetID()] = append(objects[scene.GetID()], overlay.layout)
+		overlay.layout.FetchChildrenAndFunctions(objects)
+	}
+	walkValue(scene.Args, onString, onMap)
+	for _, children := range objects {
+		for _, object := range children {
+			walkValue(object.GetArgs(), onString, onMap)
+		}
+	}
+}
+
+// walkValue walks a value from the args of an object, see walkArgs
+func walkValue(value interface{}, onString func(string), onMap func(map[string]interface{})) {
+	switch value := value.(type) {
+	case string:
+		onString(value)
+	case *NFData.NFInterfaceMap:
+		if value != nil {
+			walkValue(map[string]interface{}(value.Copy().(*NFData.NFInterfaceMap).Data), onString, onMap)
+		}
+	case NFData.CustomMap:
+		walkValue(map[string]interface{}(value), onString, onMap)
+	case map[string]interface{}:
+		if onMap != nil {
+			onMap(value)
+		}
+		for _, child := range value {
+			walkValue(child, onString, onMap)
+		}
+	case NFData.CustomSlice:
+		walkValue([]interface{}(value), onString, onMap)
+	case []interface{}:
+		for _, child := range value {
+			walkValue(child, onString, onMap)
+		}
+	}
+}
+
+// Preload reads and decodes the named scenes and preloads their assets on a background goroutine,
+// errors are logged as nothing waits for them
+func Preload(names ...string) {
+	go func() {
+		for _, name := range names {
+			err := preloadScene(name)
+			if err != nil {
+				log.Println("Error preloading scene ", name, ": ", err)
+			}
+		}
+	}()
+}
+
+// PreloadReachable preloads the scenes that can be reached from the scene that is being shown, see Scene.Reachable
+func PreloadReachable() {
+	if activeScene == nil {
+		return
+	}
+	Preload(activeScene.Reachable()...)
+}
+
+// preloadScene puts the scene in the scene cache with a spare copy decoded and preloads its assets
+func preloadScene(name string) error {
+	cached, err := cacheScene(name)
+	if err != nil {
+		return err
+	}
+	cached.fill()
+	cached.lock.Lock()
+	assets := cached.assets
+	cached.lock.Unlock()
+	return NFAsset.PreloadAll(NFAsset.Uncached(assets), nil)
+}
+
+// preloadHints preloads the scenes the scene asks for in its args,
+// args["Preload"] is a list of scene names and if args["PreloadReachable"] is true every reachable scene is preloaded
+func preloadHints(scene *Scene) {
+	if scene.Args == nil {
+		return
+	}
+	names := make([]string, 0)
+	if value, ok := scene.Args.UnTypedGet("Preload"); ok {
+		walkValue(value, func(name string) {
+			names = append(names, name)
+		}, nil)
+	}
+	var reachable bool
+	if scene.Args.Get("PreloadReachable", &reachable) == nil && reachable {
+		for _, name := range scene.Reachable() {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) > 0 {
+		Preload(names...)
+	}
+}
+
+// loadAssets shows a loading bar in the window while the assets of the scene are preloaded, returning once they all are
+func loadAssets(window fyne.Window, name string, assets []string) {
+	stopTransition()
+	//The bar is built from fyne widgets so the scene package does not depend on a widget pack
+	status := widget.NewLabel("Loading " + name)
+	status.Alignment = fyne.TextAlignCenter
+	status.TextStyle = fyne.TextStyle{Bold: true, Italic: true}
+	bar := widget.NewProgressBar()
+	bar.Max = float64(len(assets))
+	window.SetContent(container.NewVBox(layout.NewSpacer(), container.NewPadded(container.NewVBox(status, bar)), layout.NewSpacer()))
+	err := NFAsset.PreloadAll(assets, func(done, total int) {
+		bar.SetValue(float64(done))
+		status.SetText("Loading " + name + " " + strconv.Itoa(done) + "/" + strconv.Itoa(total))
+	})
+	if err != nil {
+		log.Println("Error preloading the assets of scene ", name, ": ", err)
+	}
+}
@@ -34,6 +34,8 @@ type Scene struct {
 	Layout    *NFLayout.Layout       `json:"Layout"`
 	Functions []*NFFunction.Function `json:"Functions"` // List of functions that are children of the scene for action based execution
 	Args      *NFData.NFInterfaceMap `json:"Args"`
+	Overlays  []*NFOverlay           `json:"Overlays,omitempty"` // Overlays that only belong to this scene, they are drawn with the global Overlays
+	Extends   string                 `json:"Extends,omitempty"`  // Name of the base scene whose slots the top widgets of Layout replace, see SlotType
 }
 
 func (scene *Scene) FetchAll() (map[uuid.UUID][]NFObjects.NFObject, int) {
@@ -58,6 +60,9 @@ func (scene *Scene) MakeId() {
 	for _, function := range scene.Functions {
 		function.MakeId()
 	}
+	for _, overlay := range scene.Overlays {
+		overlay.layout.MakeId()
+	}
 }
 
 func (scene *Scene) CheckArgs() error {
@@ -77,12 +82,15 @@ func (scene *Scene) Validate() error {
 		valError = errors.Join(valError, function.Validate())
 	}
 	valError = errors.Join(valError, scene.Layout.Validate())
+	for _, overlay := range scene.Overlays {
+		valError = errors.Join(valError, overlay.Validate())
+	}
 	if valError != nil {
 		log.Println("Current Scene Validation Error: ", valError)
 		if errors.Is(valError, NFError.ErrCriticalSceneValidation) {
 			return NFError.NewErrCriticalSceneValidation("Scene: " + scene.Name + " has critical validation errors and should not be loaded")
 		} else if errors.Is(valError, NFError.ErrSceneValidation) {
-			return NFError.NewErrSceneValidation("Scene: " + scene.Name + " has validation errors")
+			return errors.Join(NFError.NewErrSceneValidation("Scene: "+scene.Name+" has validation errors"), scene.validateReferences())
 		}
 	}
 	//Check if any ids appear more than once
@@ -100,7 +108,12 @@ func (scene *Scene) Validate() error {
 			return NFError.NewErrCriticalSceneValidation("Scene: " + scene.Name + " has duplicate IDs, UUID remake scheduled")
 		}
 	}
-	return nil
+	//Check that the prefabs, base scene and slots the scene references exist
+	refError := scene.validateReferences()
+	if refError != nil {
+		log.Println("Current Scene Reference Error: ", refError)
+	}
+	return refError
 }
 
 func (scene *Scene) GetID() uuid.UUID {
@@ -114,6 +127,9 @@ func (scene *Scene) FetchIDs() []uuid.UUID {
 		ids = append(ids, function.GetID())
 	}
 	ids = append(ids, scene.Layout.FetchIDs()...)
+	for _, overlay := range scene.Overlays {
+		ids = append(ids, overlay.layout.FetchIDs()...)
+	}
 	return ids
 }
 
@@ -346,21 +362,37 @@ func New(name string, layout *NFLayout.Layout, args *NFData.NFInterfaceMap) *Sce
 	}
 }
 
-// ParseAndLoad runs Parse before loading the scene in to active scene data
+// ParseAndLoad loads the scene in to active scene data before running Parse
+//
+// Once the scene data is active the scene's ActionOnLoad functions are run, errors from them are logged rather than returned.
+// The scene is parsed after them so its widgets see the variables they set, if it fails to parse the previous scene data is active again.
+// The navigation functions run the other lifecycle actions around it, see ActionOnLoad
 func (scene *Scene) ParseAndLoad(window fyne.Window) (*SceneStack, error) {
+	previousData := NFData.ActiveSceneData
+	scene.load(window)
 	sceneObject, err := scene.Parse(window)
 	if err != nil {
+		NFData.ActiveSceneData = previousData
 		return nil, err
 	}
+	return sceneObject, nil
+}
+
+// load sets the scene as the active scene data and runs its ActionOnLoad functions
+func (scene *Scene) load(window fyne.Window) {
 	NFData.ActiveSceneData = NFData.NewSceneData(scene.Name)
 	NFData.ActiveSceneData.Layouts.Set("main", *scene.Layout)
 	NFData.ActiveSceneData.Variables = scene.Args
-	return sceneObject, nil
+	_ = scene.RunLifecycleAction(ActionOnLoad, window)
 }
 
 type SceneStack struct {
 	widget.BaseWidget
 	container *fyne.Container
+	// scene is the name of the scene in the stack, it is checked against the allowed and denied scenes of the overlays
+	scene string
+	// overlays are the Overlays of the scene in the stack
+	overlays []*NFOverlay
 }
 
 type StackRenderer struct {
@@ -390,39 +422,56 @@ func (s *SceneStack) CreateRenderer() fyne.WidgetRenderer {
 }
 
 func NewSceneStack(window fyne.Window, scene fyne.CanvasObject) *SceneStack {
+	return newSceneStack(window, scene, "", nil)
+}
+
+// newSceneStack creates the stack for the named scene with the scene's own overlays,
+// if the name is empty the overlays are checked against the Current scene
+func newSceneStack(window fyne.Window, scene fyne.CanvasObject, name string, overlays []*NFOverlay) *SceneStack {
 	stack := &SceneStack{
 		container: container.NewStack(scene),
+		scene:     name,
+		overlays:  overlays,
 	}
 	stack.ExtendBaseWidget(stack)
 	stack.RefreshOverlays(window)
 	return stack
 }
 
+// RefreshOverlays parses the visible overlays again and draws them over the scene in order of their z-index,
+// modal overlays are drawn over an input blocker so nothing under them can be used
 func (s *SceneStack) RefreshOverlays(window fyne.Window) {
 	scene := s.container.Objects[0]
 	s.container.Objects = nil
 	s.Refresh()
 	s.container.Objects = append(s.container.Objects, scene)
-	for _, overlay := range Overlays {
-		if overlay.visible {
-			layout, err := overlay.layout.Parse(window)
-			if err != nil {
-				log.Println(err)
-				dialog.ShowError(err, window)
+	for _, overlay := range s.visibleOverlays() {
+		layout, err := overlay.layout.Parse(window)
+		if err != nil {
+			log.Println(err)
+			dialog.ShowError(err, window)
+			continue
+		}
+		if overlay.modal {
+			s.container.Objects = append(s.container.Objects, newInputBlocker())
+			if window != nil {
+				window.Canvas().Unfocus()
 			}
-			s.container.Objects = append(s.container.Objects, layout)
 		}
+		s.container.Objects = append(s.container.Objects, layout)
 	}
 	s.Refresh()
 }
 
 // Parse parses a scene and returns a fyne.CanvasObject that can be added to the window
+//
+// Parse runs none of the lifecycle actions, so it is safe to use for previews, use ParseAndLoad to run ActionOnLoad
 func (scene *Scene) Parse(window fyne.Window) (*SceneStack, error) {
 	layout, err := scene.Layout.Parse(window)
 	if err != nil {
 		return nil, err
 	}
-	return NewSceneStack(window, layout), err
+	return newSceneStack(window, layout, scene.Name, scene.Overlays), err
 }
 
 func (scene *Scene) Save(path string) error {
@@ -465,31 +514,52 @@ func (scene *Scene) Save(path string) error {
 }
 
 // Get gets a scene from the SceneMap loading it from the filesystem
+//
+// Scenes got with the default configuration are kept in the scene cache, so they are only read from the filesystem once,
+// every call still returns its own copy of the scene. See Preload for reading scenes before they are needed.
+// The base scene a scene Extends is resolved in to it, while Load returns scenes as they are saved for editing
 func Get(name string, config ...NFFS.Configuration) (*Scene, error) {
-	//If config[0] is not passed, use the default configuration
 	if len(config) == 0 {
-		config = append(config, NFFS.NewConfiguration(true))
-	}
-	if path, ok := SceneMap[name]; ok {
-		file, err := NFFS.Open(path, config[0])
+		cached, err := cacheScene(name)
 		if err != nil {
 			return nil, err
 		}
-		defer file.Close()
-		data, err := io.ReadAll(file)
-		if err != nil {
-			return nil, err
-		}
-		scene := &Scene{}
-		err = json.Unmarshal(data, scene)
+		return cached.take()
+	}
+	data, err := readScene(name, config[0])
+	if err != nil {
+		return nil, err
+	}
+	return decodeScene(data)
+}
+
+// readScene reads the file of the scene in the SceneMap
+func readScene(name string, config NFFS.Configuration) ([]byte, error) {
+	if path, ok := SceneMap[name]; ok {
+		file, err := NFFS.Open(path, config)
 		if err != nil {
 			return nil, err
 		}
-		return scene, nil
+		defer file.Close()
+		return io.ReadAll(file)
 	}
 	return nil, errors.New("scene not registered")
 }
 
+// decodeScene unmarshals the file of a scene and resolves the base scene it extends
+func decodeScene(data []byte) (*Scene, error) {
+	scene := &Scene{}
+	err := json.Unmarshal(data, scene)
+	if err != nil {
+		return nil, err
+	}
+	err = scene.inherit(nil)
+	if err != nil {
+		return nil, err
+	}
+	return scene, nil
+}
+
 func Load(path string) (*Scene, error) {
 	file, err := os.Open(path)
 	if err != nil {
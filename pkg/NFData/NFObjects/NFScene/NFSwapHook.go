@@ -0,0 +1,57 @@
+package NFScene
+
+import "sync"
+
+// sceneSwapHook is a hook registered with RegisterSceneSwapHook
+type sceneSwapHook struct {
+	mark    func() int
+	commit  func(mark int)
+	discard func(mark int)
+}
+
+var sceneSwapHooks []sceneSwapHook
+var sceneSwapHooksLock sync.RWMutex
+
+// RegisterSceneSwapHook lets a widget pack keep state for each parsed scene, such as the stages of CalsWidgets,
+// without the scene package depending on it.
+//
+// mark is called before a scene is parsed, its result is passed to commit once the scene replaces the one that was shown,
+// or to discard if the scene fails to parse
+func RegisterSceneSwapHook(mark func() int, commit, discard func(mark int)) {
+	sceneSwapHooksLock.Lock()
+	defer sceneSwapHooksLock.Unlock()
+	sceneSwapHooks = append(sceneSwapHooks, sceneSwapHook{mark: mark, commit: commit, discard: discard})
+}
+
+// SceneSwap holds the marks of the registered hooks from before a scene was parsed, see BeginSceneSwap
+type SceneSwap struct {
+	hooks []sceneSwapHook
+	marks []int
+}
+
+// BeginSceneSwap marks every registered hook before a scene is parsed, the navigation functions do this on their own,
+// anything else that parses scenes to show them, such as the editor's preview, should Commit or Discard the swap
+func BeginSceneSwap() SceneSwap {
+	sceneSwapHooksLock.RLock()
+	swap := SceneSwap{hooks: append([]sceneSwapHook(nil), sceneSwapHooks...)}
+	sceneSwapHooksLock.RUnlock()
+	swap.marks = make([]int, len(swap.hooks))
+	for i, hook := range swap.hooks {
+		swap.marks[i] = hook.mark()
+	}
+	return swap
+}
+
+// Commit tells every hook the scene parsed since the swap began replaced the scene that was shown
+func (swap SceneSwap) Commit() {
+	for i, hook := range swap.hooks {
+		hook.commit(swap.marks[i])
+	}
+}
+
+// Discard tells every hook the scene parsed since the swap began failed to parse and is never shown
+func (swap SceneSwap) Discard() {
+	for i, hook := range swap.hooks {
+		hook.discard(swap.marks[i])
+	}
+}
@@ -0,0 +1,411 @@
+package NFScene
+
+import (
+	"bytes"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFFS"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFStyling"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"slices"
+	"sync"
+	"time"
+)
+
+// The transitions that are registered by default
+const (
+	// TransitionNone cuts straight to the new scene
+	TransitionNone = "none"
+	// TransitionFade fades the old scene out to the Color and the new scene in from it
+	TransitionFade = "fade"
+	// TransitionCrossfade fades the old scene out over the new scene
+	TransitionCrossfade = "crossfade"
+	// TransitionDissolve is another name for TransitionCrossfade
+	TransitionDissolve = "dissolve"
+	// TransitionSlide slides the new scene in towards the Direction, pushing the old scene out
+	TransitionSlide = "slide"
+	// TransitionWipe wipes the old scene away towards the Direction, or in the order of the Mask image if there is one
+	TransitionWipe = "wipe"
+)
+
+// The directions slide and wipe transitions can move towards
+const (
+	DirectionLeft  = "left"
+	DirectionRight = "right"
+	DirectionUp    = "up"
+	DirectionDown  = "down"
+)
+
+// Easings maps the names that can be used for TransitionOptions.Easing to their curves, games can add their own
+var Easings = map[string]fyne.AnimationCurve{
+	"linear":    fyne.AnimationLinear,
+	"easeIn":    fyne.AnimationEaseIn,
+	"easeOut":   fyne.AnimationEaseOut,
+	"easeInOut": fyne.AnimationEaseInOut,
+}
+
+// DefaultTransitionDuration is used by transitions that do not set a duration
+var DefaultTransitionDuration = 500 * time.Millisecond
+
+// DefaultTransition is played when neither the navigation function nor the scene's args pick a transition
+var DefaultTransition = TransitionOptions{Name: TransitionNone}
+
+// TransitionOptions is how a transition between scenes is played
+type TransitionOptions struct {
+	// Name is the name the transition was registered with
+	Name string
+	// Duration is how long the transition takes, DefaultTransitionDuration is used if it is 0
+	Duration time.Duration
+	// Easing is the name of a curve in Easings, easeInOut is used if it is empty
+	Easing string
+	// Color is the color fade transitions fade through, black is used if it is nil
+	Color color.Color
+	// Direction is the direction slide and wipe transitions move towards, left is used for slides and right for wipes if it is empty
+	Direction string
+	// Mask is the path of a grayscale image for wipe transitions, dark pixels are wiped away first
+	Mask string
+}
+
+// TransitionFrame is what a transition is drawn with
+type TransitionFrame struct {
+	// Size is the size of the window content, From and To start at this size in the top left corner
+	Size fyne.Size
+	// From is a capture of the scene that is being left
+	From *canvas.Image
+	// To is the scene that is being shown, it is live so it keeps animating during the transition
+	To fyne.CanvasObject
+	// Options are the options the transition was picked with
+	Options TransitionOptions
+}
+
+// Transition sets up the objects drawn while the scene changes, in the order they are drawn, and returns the function that moves them.
+//
+// The objects are put in a container without a layout the size of the window, the tick function is called
+// with the eased progress from 0 to 1 and the container is refreshed after each tick.
+// Once the tick is called with 1 the container is replaced by To
+type Transition func(frame TransitionFrame) (objects []fyne.CanvasObject, tick func(progress float32))
+
+var transitions = map[string]Transition{}
+var transitionsLock sync.RWMutex
+
+// activeTransition is the transition that is playing, it is stopped if another scene is shown before it ends
+var activeTransition *fyne.Animation
+var activeTransitionLock sync.Mutex
+
+func init() {
+	_ = RegisterTransition(TransitionFade, fadeTransition)
+	_ = RegisterTransition(TransitionCrossfade, crossfadeTransition)
+	_ = RegisterTransition(TransitionDissolve, crossfadeTransition)
+	_ = RegisterTransition(TransitionSlide, slideTransition)
+	_ = RegisterTransition(TransitionWipe, wipeTransition)
+}
+
+// RegisterTransition registers a transition under the name, names must be unique
+func RegisterTransition(name string, transition Transition) error {
+	transitionsLock.Lock()
+	defer transitionsLock.Unlock()
+	if _, ok := transitions[name]; ok || name == TransitionNone {
+		return NFError.NewErrKeyAlreadyExists("transition " + name)
+	}
+	transitions[name] = transition
+	return nil
+}
+
+// GetTransition returns the transition registered under the name
+func GetTransition(name string) (Transition, bool) {
+	transitionsLock.RLock()
+	defer transitionsLock.RUnlock()
+	transition, ok := transitions[name]
+	return transition, ok
+}
+
+// GetTransitions returns the names of every registered transition
+func GetTransitions() []string {
+	transitionsLock.RLock()
+	defer transitionsLock.RUnlock()
+	names := make([]string, 0, len(transitions)+1)
+	names = append(names, TransitionNone)
+	for name := range transitions {
+		names = append(names, name)
+	}
+	slices.Sort(names[1:])
+	return names
+}
+
+// ParseTransition reads the transition options from the args of a scene or a navigation function
+//
+// The args are "Transition" with the name, "TransitionDuration" in seconds, "TransitionEasing", "TransitionColor" as
+// #RRGGBB or #RRGGBBAA, "TransitionDirection" and "TransitionMask". It returns false if the args do not name a transition
+func ParseTransition(args *NFData.NFInterfaceMap) (TransitionOptions, bool, error) {
+	options := TransitionOptions{}
+	if args == nil || args.Get("Transition", &options.Name) != nil || options.Name == "" {
+		return options, false, nil
+	}
+	if value, ok := args.UnTypedGet("TransitionDuration"); ok {
+		seconds, ok := NFData.ToFloat(value)
+		if !ok || seconds < 0 {
+			return options, false, NFError.NewErrInvalidArgument("TransitionDuration", "the duration must be a number of seconds")
+		}
+		options.Duration = time.Duration(seconds * float64(time.Second))
+	}
+	_ = args.Get("TransitionEasing", &options.Easing)
+	_ = args.Get("TransitionDirection", &options.Direction)
+	_ = args.Get("TransitionMask", &options.Mask)
+	var hex string
+	if args.Get("TransitionColor", &hex) == nil && hex != "" {
+		var err error
+		options.Color, err = NFStyling.ParseHexColor(hex)
+		if err != nil {
+			return options, false, err
+		}
+	}
+	return options, true, nil
+}
+
+// present sets the stack as the content of the window, playing the transition from the current content if there is one.
+// done is run on the UI goroutine once the stack is the content, it is not run if another scene is shown before the transition ends
+func present(window fyne.Window, stack *SceneStack, options TransitionOptions, done func()) {
+	stopTransition()
+	if options.Name == "" || options.Name == TransitionNone || window.Content() == nil {
+		window.SetContent(stack)
+		done()
+		return
+	}
+	transition, ok := GetTransition(options.Name)
+	if !ok {
+		log.Println("Transition ", options.Name, " is not registered, changing scene without it")
+		window.SetContent(stack)
+		done()
+		return
+	}
+	duration := options.Duration
+	if duration <= 0 {
+		duration = DefaultTransitionDuration
+	}
+	curve, ok := Easings[options.Easing]
+	if !ok {
+		curve = fyne.AnimationEaseInOut
+	}
+
+	//Drivers that can not capture the window, such as headless ones, change scene without the transition
+	capture := captureContent(window)
+	if capture == nil {
+		window.SetContent(stack)
+		done()
+		return
+	}
+	size := window.Content().Size()
+	from := canvas.NewImageFromImage(capture)
+	from.FillMode = canvas.ImageFillStretch
+	from.ScaleMode = canvas.ImageScaleFastest
+	for _, object := range []fyne.CanvasObject{from, stack} {
+		object.Resize(size)
+		object.Move(fyne.NewPos(0, 0))
+	}
+	objects, tick := transition(TransitionFrame{Size: size, From: from, To: stack, Options: options})
+	frame := container.NewWithoutLayout(objects...)
+	frame.Resize(size)
+	tick(0)
+	window.SetContent(frame)
+
+	var animation *fyne.Animation
+	animation = fyne.NewAnimation(duration, func(progress float32) {
+		tick(progress)
+		frame.Refresh()
+		if progress < 1 {
+			return
+		}
+		//The animation runs on its own goroutine, the scene is finished on the UI goroutine as done autosaves the active save.
+		//A scene shown before it runs stops the transition, so the finished transition does not replace it
+		NFData.RunOnUI(window, func() {
+			activeTransitionLock.Lock()
+			finished := activeTransition == animation
+			if finished {
+				activeTransition = nil
+			}
+			activeTransitionLock.Unlock()
+			if finished {
+				stack.Move(fyne.NewPos(0, 0))
+				window.SetContent(stack)
+				done()
+			}
+		})
+	})
+	animation.Curve = curve
+	activeTransitionLock.Lock()
+	activeTransition = animation
+	activeTransitionLock.Unlock()
+	animation.Start()
+}
+
+// stopTransition stops the transition that is playing so it does not replace the content of the window when it ends
+func stopTransition() {
+	activeTransitionLock.Lock()
+	defer activeTransitionLock.Unlock()
+	if activeTransition != nil {
+		activeTransition.Stop()
+		activeTransition = nil
+	}
+}
+
+// captureContent captures the part of the window that shows its content, leaving out anything drawn around it such as a menu
+func captureContent(window fyne.Window) image.Image {
+	c := window.Canvas()
+	capture := c.Capture()
+	content := c.Content()
+	if capture == nil || content == nil || c.Size().Width <= 0 {
+		return capture
+	}
+	scale := float32(capture.Bounds().Dx()) / c.Size().Width
+	position := fyne.CurrentApp().Driver().AbsolutePositionForObject(content)
+	size := content.Size()
+	bounds := image.Rect(
+		int(position.X*scale), int(position.Y*scale),
+		int((position.X+size.Width)*scale), int((position.Y+size.Height)*scale),
+	).Add(capture.Bounds().Min).Intersect(capture.Bounds())
+	if sub, ok := capture.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok && !bounds.Empty() {
+		return sub.SubImage(bounds)
+	}
+	return capture
+}
+
+// fadeTransition fades out to the color over the first half and in from it over the second half
+func fadeTransition(frame TransitionFrame) ([]fyne.CanvasObject, func(float32)) {
+	fill := color.NRGBAModel.Convert(color.Black).(color.NRGBA)
+	if frame.Options.Color != nil {
+		fill = color.NRGBAModel.Convert(frame.Options.Color).(color.NRGBA)
+	}
+	cover := canvas.NewRectangle(fill)
+	cover.Resize(frame.Size)
+	return []fyne.CanvasObject{frame.From, frame.To, cover}, func(progress float32) {
+		alpha := progress * 2
+		if progress >= 0.5 {
+			alpha = (1 - progress) * 2
+			frame.From.Hide()
+			frame.To.Show()
+		} else {
+			frame.From.Show()
+			frame.To.Hide()
+		}
+		cover.FillColor = color.NRGBA{R: fill.R, G: fill.G, B: fill.B, A: uint8(float32(fill.A) * alpha)}
+	}
+}
+
+// crossfadeTransition fades the capture of the old scene out over the new scene
+func crossfadeTransition(frame TransitionFrame) ([]fyne.CanvasObject, func(float32)) {
+	return []fyne.CanvasObject{frame.To, frame.From}, func(progress float32) {
+		frame.From.Translucency = float64(progress)
+	}
+}
+
+// directionVector returns the unit vector of the direction, or the fallback if the direction is empty or unknown
+func directionVector(direction, fallback string) (float32, float32) {
+	switch direction {
+	case DirectionLeft:
+		return -1, 0
+	case DirectionRight:
+		return 1, 0
+	case DirectionUp:
+		return 0, -1
+	case DirectionDown:
+		return 0, 1
+	}
+	return directionVector(fallback, DirectionLeft)
+}
+
+// slideTransition moves both scenes towards the direction, the new scene comes in from the opposite side
+func slideTransition(frame TransitionFrame) ([]fyne.CanvasObject, func(float32)) {
+	dx, dy := directionVector(frame.Options.Direction, DirectionLeft)
+	width, height := frame.Size.Width*dx, frame.Size.Height*dy
+	return []fyne.CanvasObject{frame.From, frame.To}, func(progress float32) {
+		frame.From.Move(fyne.NewPos(width*progress, height*progress))
+		frame.To.Move(fyne.NewPos(width*(progress-1), height*(progress-1)))
+	}
+}
+
+// wipeSoftness is how much of the mask is blended at the edge of a wipe, from 0 for a hard edge to 1
+const wipeSoftness = 0.1
+
+// wipeTransition draws the capture of the old scene over the new scene, hiding the pixels whose mask value
+// the progress has passed. Without a Mask image the mask is a gradient towards the direction
+func wipeTransition(frame TransitionFrame) ([]fyne.CanvasObject, func(float32)) {
+	if frame.From == nil || frame.From.Image == nil {
+		//There is nothing to wipe away, so the new scene is shown straight away
+		return []fyne.CanvasObject{frame.To}, func(float32) {}
+	}
+	from := frame.From.Image
+	bounds := from.Bounds()
+	mask := directionalMask(frame.Options.Direction)
+	if frame.Options.Mask != "" {
+		maskImage, err := loadMask(frame.Options.Mask)
+		if err != nil {
+			log.Println("Error loading wipe mask, wiping towards the direction instead: ", err)
+		} else {
+			mask = imageMask(maskImage)
+		}
+	}
+	var progress float32
+	raster := canvas.NewRasterWithPixels(func(x, y, w, h int) color.Color {
+		if w <= 0 || h <= 0 {
+			return color.Transparent
+		}
+		u, v := (float32(x)+0.5)/float32(w), (float32(y)+0.5)/float32(h)
+		edge := progress*(1+wipeSoftness) - wipeSoftness
+		alpha := min(max((mask(u, v)-edge)/wipeSoftness, 0), 1)
+		if alpha <= 0 {
+			return color.Transparent
+		}
+		pixel := color.NRGBAModel.Convert(from.At(bounds.Min.X+int(u*float32(bounds.Dx())), bounds.Min.Y+int(v*float32(bounds.Dy())))).(color.NRGBA)
+		pixel.A = uint8(float32(pixel.A) * alpha)
+		return pixel
+	})
+	raster.Resize(frame.Size)
+	return []fyne.CanvasObject{frame.To, raster}, func(p float32) {
+		progress = p
+	}
+}
+
+// directionalMask returns a mask that wipes towards the direction, right if it is empty
+func directionalMask(direction string) func(u, v float32) float32 {
+	dx, dy := directionVector(direction, DirectionRight)
+	return func(u, v float32) float32 {
+		switch {
+		case dx > 0:
+			return u
+		case dx < 0:
+			return 1 - u
+		case dy > 0:
+			return v
+		default:
+			return 1 - v
+		}
+	}
+}
+
+// imageMask returns a mask that reads the brightness of the image, it is stretched over the scene
+func imageMask(maskImage image.Image) func(u, v float32) float32 {
+	bounds := maskImage.Bounds()
+	return func(u, v float32) float32 {
+		gray := color.GrayModel.Convert(maskImage.At(bounds.Min.X+int(u*float32(bounds.Dx())), bounds.Min.Y+int(v*float32(bounds.Dy())))).(color.Gray)
+		return float32(gray.Y) / 255
+	}
+}
+
+// loadMask loads a mask image from the game's files
+func loadMask(path string) (image.Image, error) {
+	maskBytes, err := NFFS.ReadFile(path, NFFS.NewConfiguration(true))
+	if err != nil {
+		return nil, err
+	}
+	maskImage, _, err := image.Decode(bytes.NewReader(maskBytes))
+	return maskImage, err
+}
@@ -0,0 +1,90 @@
+package CalsWidgets
+
+import (
+	"fyne.io/fyne/v2"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFScene"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget"
+	"log"
+)
+
+// Import is an empty function, created to allow the inclusion of this package in other parts of the code,
+// even if none of its functions are directly used.
+// This ensures that the init function is executed without triggering warnings about unused imports.
+//
+// While it's possible to import a package for its side effects by changing its alias to _,
+// using the Import function provides the added benefit of retaining direct access to the package's contents.
+func Import() {}
+
+// This init() registers Cal's widgets to be used within the game
+// In go the init function is called when the package is imported, but in order
+// to avoid unused import warnings, you can call the empty Import() function, which does nothing
+func init() {
+	Import()
+	log.Println("Registering Cal's Widgets")
+
+	// NarrativeBoxHandler, the optional args match the fields of SafeNarrativeBox with colors as hex strings
+	defaults := NewJsonSafeDialog()
+	narrativeBox := NFWidget.Widget{
+		Type:         "NarrativeBox",
+		RequiredArgs: NFData.NewNFInterfaceMap(NFData.NewKeyVal("AllText", []interface{}{""})),
+		OptionalArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("State", defaults.State),
+			NFData.NewKeyVal("Name", defaults.Name),
+			NFData.NewKeyVal("HasName", defaults.HasName),
+			NFData.NewKeyVal("StrokeColor", hexColor(defaults.StrokeColor)),
+			NFData.NewKeyVal("NameStrokeColor", hexColor(defaults.NameStrokeColor)),
+			NFData.NewKeyVal("Fill", hexColor(defaults.Fill)),
+			NFData.NewKeyVal("NameFill", hexColor(defaults.NameFill)),
+			NFData.NewKeyVal("Stroke", defaults.Stroke),
+			NFData.NewKeyVal("NameStroke", defaults.NameStroke),
+			NFData.NewKeyVal("NamePosition", defaults.NamePosition),
+			NFData.NewKeyVal("NameAffectsLayout", defaults.NameAffectsLayout),
+			NFData.NewKeyVal("StateOnTap", defaults.StateOnTap),
+			NFData.NewKeyVal("TextOnStateChange", defaults.TextOnStateChange),
+			NFData.NewKeyVal("ConcatText", defaults.ConcatText),
+			NFData.NewKeyVal("AnimateText", defaults.AnimateText),
+			NFData.NewKeyVal("CanSkip", defaults.CanSkip),
+			NFData.NewKeyVal("TextDelay", defaults.TextDelay),
+			NFData.NewKeyVal("OnStateChange", "OnStateChange"),
+			NFData.NewKeyVal("OnTapped", "OnTapped"),
+			NFData.NewKeyVal("OnSecondaryTapped", ""),
+			NFData.NewKeyVal("OnDoubleTapped", ""),
+			NFData.NewKeyVal("OnHover", ""),
+			NFData.NewKeyVal("OnEndHover", ""),
+			NFData.NewKeyVal("WhileHover", ""),
+			NFData.NewKeyVal("Sizing", defaults.Sizing),
+			NFData.NewKeyVal("Padding", defaults.Padding),
+			NFData.NewKeyVal("ExternalPadding", defaults.ExternalPadding),
+			NFData.NewKeyVal("ContentStyle", defaults.ContentStyle),
+			NFData.NewKeyVal("NameStyle", defaults.NameStyle),
+			NFData.NewKeyVal("NamePadding", defaults.NamePadding),
+			NFData.NewKeyVal("NameSizing", defaults.NameSizing),
+			NFData.NewKeyVal("Character", ""),
+			NFData.NewKeyVal("Voice", []interface{}{""}),
+			NFData.NewKeyVal("StateKey", ""),
+			NFData.NewKeyVal("Hidden", false),
+			NFData.NewKeyVal("Position", fyne.NewPos(0, 0)),
+			NFData.NewKeyVal("Size", fyne.NewSize(0, 0)),
+		),
+	}
+	narrativeBox.Register(NarrativeBoxHandler)
+
+	// StageHandler, characters are shown on it with the ShowCharacter, HideCharacter and SetExpression functions
+	stage := NFWidget.Widget{
+		Type:         "Stage",
+		RequiredArgs: NFData.NewNFInterfaceMap(),
+		OptionalArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("StageKey", DefaultStageKey),
+			NFData.NewKeyVal("Slots", map[string]interface{}{}),
+			NFData.NewKeyVal("SpriteWidth", 0.3),
+			NFData.NewKeyVal("SpriteHeight", 0.9),
+			NFData.NewKeyVal("Hidden", false),
+			NFData.NewKeyVal("Position", fyne.NewPos(0, 0)),
+			NFData.NewKeyVal("Size", fyne.NewSize(0, 0)),
+		),
+	}
+	stage.Register(StageHandler)
+	//The stages of a scene are dropped once another scene replaces it, or straight away if it fails to parse
+	NFScene.RegisterSceneSwapHook(StageMark, ReplaceStages, DiscardStages)
+}
@@ -0,0 +1,238 @@
+package CalsWidgets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFCharacter"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFFunction"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFStyling"
+	"image/color"
+	"log"
+)
+
+// narrativeBoxArgs is SafeNarrativeBox as it is written in scene files, color.Color can not be unmarshalled
+// so the color fields are shadowed by hex strings such as "#808080FF"
+type narrativeBoxArgs struct {
+	SafeNarrativeBox
+	StrokeColor     string `json:"StrokeColor"`
+	NameStrokeColor string `json:"NameStrokeColor"`
+	Fill            string `json:"Fill"`
+	NameFill        string `json:"NameFill"`
+}
+
+// hexColor converts a color to a #RRGGBBAA string
+func hexColor(c color.Color) string {
+	r, g, b, a := color.NRGBAModel.Convert(c).RGBA()
+	return fmt.Sprintf("#%02X%02X%02X%02X", r>>8, g>>8, b>>8, a>>8)
+}
+
+// ParseSafeNarrativeBox converts widget args in to a SafeNarrativeBox, any field that is not in the args keeps its default
+//
+// Args can hold NFExpressions, as can the lines in AllText.
+// If args["Character"] is the ID of a registered character its name, name plate colors and name style are used
+// for any of those fields that are not in the args
+func ParseSafeNarrativeBox(args *NFData.NFInterfaceMap) (SafeNarrativeBox, error) {
+	defaults := NewJsonSafeDialog()
+	parsed := narrativeBoxArgs{SafeNarrativeBox: defaults}
+	data := make(map[string]interface{})
+	var characterID string
+	if args.Get("Character", &characterID) == nil && characterID != "" {
+		character, err := NFCharacter.Get(characterID)
+		if err != nil {
+			return defaults, err
+		}
+		name, err := character.DisplayName()
+		if err != nil {
+			return defaults, err
+		}
+		data["Name"] = name
+		data["HasName"] = true
+		data["NameStyle"] = character.NameStyle
+		if character.NameColor != "" {
+			data["NameFill"] = character.NameColor
+		}
+		if character.NameStrokeColor != "" {
+			data["NameStrokeColor"] = character.NameStrokeColor
+		}
+	}
+	for key, value := range args.Copy().(*NFData.NFInterfaceMap).Data {
+		value, err := NFData.Evaluate(value)
+		if err != nil {
+			return defaults, err
+		}
+		//The lines are evaluated in to a new slice, the slice in the args may still be the widget's own
+		//and writing the evaluated lines back in to it would lose the expressions when the scene is saved
+		if lines, ok := value.([]interface{}); ok && key == "AllText" {
+			evaluated := make([]interface{}, len(lines))
+			for i, line := range lines {
+				evaluated[i], err = NFData.Evaluate(line)
+				if err != nil {
+					return defaults, err
+				}
+			}
+			value = evaluated
+		}
+		data[key] = value
+	}
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return defaults, err
+	}
+	err = json.Unmarshal(jsonBytes, &parsed)
+	if err != nil {
+		return defaults, err
+	}
+	safe := parsed.SafeNarrativeBox
+	colors := []struct {
+		hex    string
+		target *color.Color
+	}{
+		{parsed.StrokeColor, &safe.StrokeColor},
+		{parsed.NameStrokeColor, &safe.NameStrokeColor},
+		{parsed.Fill, &safe.Fill},
+		{parsed.NameFill, &safe.NameFill},
+	}
+	for _, c := range colors {
+		if c.hex == "" {
+			continue
+		}
+		*c.target, err = NFStyling.ParseHexColor(c.hex)
+		if err != nil {
+			return defaults, err
+		}
+	}
+	return safe, nil
+}
+
+// NarrativeBoxStateKey returns the key used to remember the state of a NarrativeBox in the active save
+func NarrativeBoxStateKey(w *NFWidget.Widget) string {
+	var key string
+	if w.Args != nil && w.Args.Get("StateKey", &key) == nil && key != "" {
+		return key
+	}
+	return "NarrativeBox." + w.GetID().String()
+}
+
+// NarrativeBoxHandler creates a NarrativeBox from args matching the fields of SafeNarrativeBox
+//
+// The OnStateChange, OnTapped, OnSecondaryTapped, OnDoubleTapped, OnHover, OnEndHover and WhileHover args are the names of
+// actions that are run on the widget's functions, OnStateChange is passed the new "State".
+// The state is kept in the active save, so resuming a save shows the line the player was on,
+// and every line shown is recorded in the save's backlog.
+// If args["Character"] is a registered character, args["Voice"] can list a voice line file for each line of AllText,
+// it is played with the character's voice settings when the line is shown, an empty file plays nothing
+func NarrativeBoxHandler(window fyne.Window, args *NFData.NFInterfaceMap, w *NFWidget.Widget) (fyne.CanvasObject, error) {
+	safe, err := ParseSafeNarrativeBox(args)
+	if err != nil {
+		return nil, NFError.NewErrWidgetParse(w.GetName(), w.GetType(), w.GetID(), err.Error())
+	}
+	n := &NarrativeBox{SafeNarrativeBox: safe}
+	n.ExtendBaseWidget(n)
+
+	if n.TextOnStateChange && len(n.AllText) > 0 {
+		n.MaxState = len(n.AllText) - 1
+	}
+
+	stateKey := NarrativeBoxStateKey(w)
+	if NFSave.Active != nil {
+		if state, ok := NFSave.Active.GetSceneState(stateKey); ok {
+			n.State = state
+		}
+	}
+
+	var characterID string
+	_ = args.Get("Character", &characterID)
+	var voices []interface{}
+	_ = args.Get("Voice", &voices)
+	playVoice := func(state int) {
+		if characterID == "" || state >= len(voices) {
+			return
+		}
+		voice, ok := voices[state].(string)
+		if !ok || voice == "" {
+			return
+		}
+		character, err := NFCharacter.Get(characterID)
+		if err == nil {
+			err = character.PlayVoice(voice)
+		}
+		if err != nil {
+			log.Println("Error playing voice line ", voice, " for narrative box ", w.GetName(), ": ", err)
+		}
+	}
+
+	//Each line the box shows is added to the backlog of the active save and marked as read for every save,
+	//voice lines only play in the game so previews in the editor stay silent
+	recordLine := func(state int) {
+		if NFSave.Active == nil || state < 0 || state >= len(n.AllText) {
+			return
+		}
+		speaker := ""
+		if n.HasName {
+			speaker = n.Name
+		}
+		NFSave.Active.RecordLine(speaker, n.AllText[state])
+		NFSave.Persistent.MarkRead(NFSave.Active.Scene, speaker, n.AllText[state])
+
+		playVoice(state)
+	}
+	recordLine(n.State)
+
+	runAction := func(action string, values *NFData.NFInterfaceMap) {
+		if action == "" {
+			return
+		}
+		results, err := w.RunAction(action, window, values)
+		if err != nil && !errors.Is(err, NFError.ErrNotImplemented) {
+			if results == nil {
+				results = NFData.NewNFInterfaceMap()
+			}
+			results.Set("Error", fmt.Sprintf("Error running %s for narrative box %s: %s", action, w.GetName(), err.Error()))
+			_, _ = NFFunction.ParseAndRun(window, "Error", results)
+		}
+	}
+	actionFunc := func(action string) func() {
+		if action == "" {
+			return nil
+		}
+		return func() { runAction(action, nil) }
+	}
+	n.OnStateChange = func(state int) {
+		if NFSave.Active != nil {
+			NFSave.Active.SetSceneState(stateKey, state)
+		}
+		recordLine(state)
+		runAction(safe.OnStateChange, NFData.NewNFInterfaceMap(NFData.NewKeyVal("State", state)))
+	}
+	n.OnTapped = actionFunc(safe.OnTapped)
+	n.OnSecondaryTapped = actionFunc(safe.OnSecondaryTapped)
+	n.OnDoubleTapped = actionFunc(safe.OnDoubleTapped)
+	n.OnHover = actionFunc(safe.OnHover)
+	n.OnEndHover = actionFunc(safe.OnEndHover)
+	n.WhileHover = actionFunc(safe.WhileHover)
+
+	var hidden = false
+	err = args.Get("Hidden", &hidden)
+	if err == nil && hidden {
+		n.Hide()
+	}
+
+	var position = n.Position()
+	err = args.Get("Position", &position)
+	if err == nil {
+		n.Move(position)
+	}
+
+	var size = n.Size()
+	err = args.Get("Size", &size)
+	if err == nil {
+		n.Resize(size)
+	}
+	return n, nil
+}
@@ -0,0 +1,387 @@
+package CalsWidgets
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/widget"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFAsset"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFCharacter"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+	"log"
+	"slices"
+	"sync"
+	"time"
+)
+
+// The transitions a character can enter, leave or change slots with
+const (
+	TransitionNone  = "none"
+	TransitionFade  = "fade"
+	TransitionSlide = "slide"
+)
+
+// StageAnchor is a place on a stage given as fractions of its size, X is the center of the sprite and Y is its bottom
+type StageAnchor struct {
+	X float32 `json:"X"`
+	Y float32 `json:"Y"`
+}
+
+// DefaultStageAnchors are the slots every stage has, more can be added with the Slots arg
+func DefaultStageAnchors() map[string]StageAnchor {
+	return map[string]StageAnchor{
+		"left":   {X: 0.2, Y: 1},
+		"center": {X: 0.5, Y: 1},
+		"right":  {X: 0.8, Y: 1},
+	}
+}
+
+// stages holds the stages parsed for each key with the newest last, so functions can find the one on screen.
+// The stages of a scene that is replaced are dropped with ReplaceStages, which is registered as an NFScene swap hook
+var stages = map[string][]*Stage{}
+var stagesLock sync.Mutex
+
+// stageCount is the number of stages that have been added to stages
+var stageCount int
+
+// addStage makes the stage the one GetStage returns for its key
+func addStage(stage *Stage) {
+	stagesLock.Lock()
+	defer stagesLock.Unlock()
+	stageCount++
+	stage.mark = stageCount
+	stages[stage.Key] = append(stages[stage.Key], stage)
+}
+
+// GetStage returns the newest stage parsed with the key
+func GetStage(key string) (*Stage, bool) {
+	stagesLock.Lock()
+	defer stagesLock.Unlock()
+	parsed := stages[key]
+	if len(parsed) == 0 {
+		return nil, false
+	}
+	return parsed[len(parsed)-1], true
+}
+
+// StageMark returns a mark for the stages parsed so far, a scene takes one before it parses its layout and overlays
+// and passes it to ReplaceStages once it is shown, or to DiscardStages if it fails to parse, see NFScene.RegisterSceneSwapHook
+func StageMark() int {
+	stagesLock.Lock()
+	defer stagesLock.Unlock()
+	return stageCount
+}
+
+// ReplaceStages forgets the stages parsed before the mark, they belong to the scene that was replaced
+func ReplaceStages(mark int) {
+	dropStages(func(stage *Stage) bool { return stage.mark <= mark })
+}
+
+// DiscardStages forgets the stages parsed after the mark, they belong to a scene that was never shown
+func DiscardStages(mark int) {
+	dropStages(func(stage *Stage) bool { return stage.mark > mark })
+}
+
+// dropStages removes the stages drop returns true for
+func dropStages(drop func(*Stage) bool) {
+	stagesLock.Lock()
+	defer stagesLock.Unlock()
+	for key, parsed := range stages {
+		parsed = slices.DeleteFunc(parsed, drop)
+		if len(parsed) == 0 {
+			delete(stages, key)
+			continue
+		}
+		stages[key] = parsed
+	}
+}
+
+// stageSprite is a character on a stage, x and y are the fractions of the stage its anchor is currently at
+type stageSprite struct {
+	NFSave.StageCharacter
+	image *canvas.Image
+	x, y  float32
+	anim  *fyne.Animation
+	// leaving is set while the sprite's exit animation runs, it is no longer part of the stage's state
+	leaving bool
+}
+
+// Stage is a widget that shows characters in named slots and animates them entering, leaving and moving between slots
+type Stage struct {
+	widget.BaseWidget
+
+	// Key is the name the stage is kept under in the active save, scenes that share a key share the characters on screen
+	Key     string
+	Anchors map[string]StageAnchor
+	// SpriteWidth and SpriteHeight are the size of each character as fractions of the stage size
+	SpriteWidth  float32
+	SpriteHeight float32
+
+	lock    sync.Mutex
+	sprites []*stageSprite
+	// mark is the order the stage was added to stages in
+	mark int
+}
+
+// NewStage creates an empty stage with the default anchors
+func NewStage(key string) *Stage {
+	s := &Stage{Key: key, Anchors: DefaultStageAnchors(), SpriteWidth: 0.3, SpriteHeight: 0.9}
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+// spriteImage loads the image at path, or at assets/image/path like the Image widget
+func spriteImage(path string) (*canvas.Image, error) {
+	image, err := NFAsset.Image(path)
+	if err != nil {
+		return nil, err
+	}
+	image.FillMode = canvas.ImageFillContain
+	return image, nil
+}
+
+// expressionImage loads the image of a character's expression
+func expressionImage(characterID, expression string) (*canvas.Image, error) {
+	character, err := NFCharacter.Get(characterID)
+	if err != nil {
+		return nil, err
+	}
+	path, err := character.Expression(expression)
+	if err != nil {
+		return nil, err
+	}
+	return spriteImage(path)
+}
+
+// find returns the sprite of the character, the caller must hold the lock
+func (s *Stage) find(character string) (*stageSprite, int) {
+	for i, sprite := range s.sprites {
+		if sprite.Character == character && !sprite.leaving {
+			return sprite, i
+		}
+	}
+	return nil, -1
+}
+
+// Characters returns the characters on the stage in the order they are drawn
+func (s *Stage) Characters() []NFSave.StageCharacter {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	characters := make([]NFSave.StageCharacter, 0, len(s.sprites))
+	for _, sprite := range s.sprites {
+		if !sprite.leaving {
+			characters = append(characters, sprite.StageCharacter)
+		}
+	}
+	return characters
+}
+
+// save stores the characters on the stage in the active save
+func (s *Stage) save() {
+	if NFSave.Active != nil {
+		NFSave.Active.SetStage(s.Key, s.Characters())
+	}
+}
+
+// animate runs tick over the duration on the sprite, stopping any animation it was already running
+//
+// tick is run while holding the lock
+func (s *Stage) animate(sprite *stageSprite, duration time.Duration, tick func(float32)) {
+	s.lock.Lock()
+	if sprite.anim != nil {
+		sprite.anim.Stop()
+		sprite.anim = nil
+	}
+	if duration <= 0 {
+		tick(1)
+		s.lock.Unlock()
+		s.Refresh()
+		return
+	}
+	anim := fyne.NewAnimation(duration, func(f float32) {
+		s.lock.Lock()
+		tick(f)
+		s.lock.Unlock()
+		s.Refresh()
+	})
+	anim.Curve = fyne.AnimationEaseInOut
+	sprite.anim = anim
+	s.lock.Unlock()
+	anim.Start()
+}
+
+// ShowCharacter puts the character on the stage in the slot with the expression, a character that is already on the stage
+// changes its expression and moves to the slot
+func (s *Stage) ShowCharacter(character, slot, expression, transition string, duration time.Duration) error {
+	anchor, ok := s.Anchors[slot]
+	if !ok {
+		return NFError.NewErrInvalidArgument("Slot", "the stage has no slot "+slot)
+	}
+	image, err := expressionImage(character, expression)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	sprite, _ := s.find(character)
+	if sprite != nil {
+		image.Translucency = sprite.image.Translucency
+		sprite.image = image
+		sprite.Expression = expression
+		sprite.Slot = slot
+		startX, startY := sprite.x, sprite.y
+		s.lock.Unlock()
+		if transition == TransitionNone {
+			duration = 0
+		}
+		s.animate(sprite, duration, func(f float32) {
+			sprite.x = startX + (anchor.X-startX)*f
+			sprite.y = startY + (anchor.Y-startY)*f
+		})
+		s.save()
+		return nil
+	}
+	sprite = &stageSprite{
+		StageCharacter: NFSave.StageCharacter{Character: character, Expression: expression, Slot: slot},
+		image:          image,
+		x:              anchor.X,
+		y:              anchor.Y,
+	}
+	s.sprites = append(s.sprites, sprite)
+	s.lock.Unlock()
+	switch transition {
+	case TransitionFade:
+		s.animate(sprite, duration, func(f float32) {
+			sprite.image.Translucency = float64(1 - f)
+		})
+	case TransitionSlide:
+		//Characters slide in from the closest side of the stage
+		startX := -s.SpriteWidth
+		if anchor.X > 0.5 {
+			startX = 1 + s.SpriteWidth
+		}
+		s.animate(sprite, duration, func(f float32) {
+			sprite.x = startX + (anchor.X-startX)*f
+		})
+	default:
+		s.animate(sprite, 0, func(float32) {})
+	}
+	s.save()
+	return nil
+}
+
+// HideCharacter takes the character off the stage
+func (s *Stage) HideCharacter(character, transition string, duration time.Duration) error {
+	s.lock.Lock()
+	sprite, index := s.find(character)
+	if sprite == nil {
+		s.lock.Unlock()
+		return NFError.NewErrNotFound("character " + character + " is not on the stage")
+	}
+	if transition == TransitionNone || duration <= 0 {
+		s.sprites = slices.Delete(s.sprites, index, index+1)
+		s.lock.Unlock()
+		s.Refresh()
+		s.save()
+		return nil
+	}
+	//The character leaves the saved state straight away, and the sprites once it is off the stage
+	sprite.leaving = true
+	startX := sprite.x
+	s.lock.Unlock()
+	endX := -s.SpriteWidth
+	if startX > 0.5 {
+		endX = 1 + s.SpriteWidth
+	}
+	s.animate(sprite, duration, func(f float32) {
+		if transition == TransitionSlide {
+			sprite.x = startX + (endX-startX)*f
+		} else {
+			sprite.image.Translucency = float64(f)
+		}
+		if f >= 1 {
+			if i := slices.Index(s.sprites, sprite); i >= 0 {
+				s.sprites = slices.Delete(s.sprites, i, i+1)
+			}
+		}
+	})
+	s.save()
+	return nil
+}
+
+// SetExpression changes the expression of a character that is on the stage
+func (s *Stage) SetExpression(character, expression string) error {
+	image, err := expressionImage(character, expression)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	sprite, _ := s.find(character)
+	if sprite == nil {
+		s.lock.Unlock()
+		return NFError.NewErrNotFound("character " + character + " is not on the stage")
+	}
+	image.Translucency = sprite.image.Translucency
+	sprite.image = image
+	sprite.Expression = expression
+	s.lock.Unlock()
+	s.Refresh()
+	s.save()
+	return nil
+}
+
+// Restore shows the characters without any transition, characters that can not be shown are logged and skipped
+func (s *Stage) Restore(characters []NFSave.StageCharacter) {
+	for _, character := range characters {
+		err := s.ShowCharacter(character.Character, character.Slot, character.Expression, TransitionNone, 0)
+		if err != nil {
+			log.Println("Error restoring ", character.Character, " on stage ", s.Key, ": ", err)
+		}
+	}
+}
+
+func (s *Stage) CreateRenderer() fyne.WidgetRenderer {
+	r := &stageRenderer{stage: s}
+	r.Refresh()
+	return r
+}
+
+type stageRenderer struct {
+	stage   *Stage
+	objects []fyne.CanvasObject
+}
+
+func (r *stageRenderer) Destroy() {}
+
+func (r *stageRenderer) Layout(size fyne.Size) {
+	s := r.stage
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	spriteSize := fyne.NewSize(size.Width*s.SpriteWidth, size.Height*s.SpriteHeight)
+	for _, sprite := range s.sprites {
+		sprite.image.Resize(spriteSize)
+		sprite.image.Move(fyne.NewPos(sprite.x*size.Width-spriteSize.Width/2, sprite.y*size.Height-spriteSize.Height))
+	}
+}
+
+func (r *stageRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(100, 100)
+}
+
+func (r *stageRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *stageRenderer) Refresh() {
+	s := r.stage
+	s.lock.Lock()
+	objects := make([]fyne.CanvasObject, 0, len(s.sprites))
+	for _, sprite := range s.sprites {
+		objects = append(objects, sprite.image)
+	}
+	s.lock.Unlock()
+	r.objects = objects
+	r.Layout(s.Size())
+	for _, object := range objects {
+		canvas.Refresh(object)
+	}
+}
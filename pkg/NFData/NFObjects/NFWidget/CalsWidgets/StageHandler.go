@@ -0,0 +1,149 @@
+package CalsWidgets
+
+import (
+	"fyne.io/fyne/v2"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFCharacter"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+	"slices"
+	"time"
+)
+
+// DefaultStageKey is the key used by stages and stage functions that do not set one
+const DefaultStageKey = "Stage"
+
+// StageHandler creates a Stage that shows the characters kept in the active save under args["StageKey"]
+//
+// args["Slots"] adds or replaces anchors, as a map of slot names to {"X": 0.5, "Y": 1} fractions of the stage,
+// and args["SpriteWidth"] and args["SpriteHeight"] set the size of the characters as fractions of the stage
+func StageHandler(window fyne.Window, args *NFData.NFInterfaceMap, w *NFWidget.Widget) (fyne.CanvasObject, error) {
+	key := DefaultStageKey
+	_ = args.Get("StageKey", &key)
+	stage := NewStage(key)
+
+	if rawSlots, ok := args.UnTypedGet("Slots"); ok {
+		slots, ok := NFData.AsMap(rawSlots)
+		if !ok {
+			return nil, NFError.NewErrWidgetParse(w.GetName(), w.GetType(), w.GetID(), "Slots must be a map")
+		}
+		for name, rawAnchor := range slots {
+			anchor, ok := NFData.AsMap(rawAnchor)
+			x, xOk := NFData.ToFloat(anchor["X"])
+			y, yOk := NFData.ToFloat(anchor["Y"])
+			if !ok || !xOk || !yOk {
+				return nil, NFError.NewErrWidgetParse(w.GetName(), w.GetType(), w.GetID(), "slot "+name+" must have a number X and Y")
+			}
+			stage.Anchors[name] = StageAnchor{X: float32(x), Y: float32(y)}
+		}
+	}
+	var spriteWidth, spriteHeight float64
+	if args.Get("SpriteWidth", &spriteWidth) == nil {
+		stage.SpriteWidth = float32(spriteWidth)
+	}
+	if args.Get("SpriteHeight", &spriteHeight) == nil {
+		stage.SpriteHeight = float32(spriteHeight)
+	}
+
+	if NFSave.Active != nil {
+		stage.Restore(NFSave.Active.GetStage(key))
+	}
+	addStage(stage)
+
+	var hidden = false
+	err := args.Get("Hidden", &hidden)
+	if err == nil && hidden {
+		stage.Hide()
+	}
+
+	var position = stage.Position()
+	err = args.Get("Position", &position)
+	if err == nil {
+		stage.Move(position)
+	}
+
+	var size = stage.Size()
+	err = args.Get("Size", &size)
+	if err == nil {
+		stage.Resize(size)
+	}
+	return stage, nil
+}
+
+// updateSavedStage changes the characters kept in the active save for a stage that is not on screen
+func updateSavedStage(key string, update func([]NFSave.StageCharacter) ([]NFSave.StageCharacter, error)) error {
+	if NFSave.Active == nil {
+		return NFError.NewErrNotFound("no stage " + key + " and no active save to keep it in")
+	}
+	characters, err := update(NFSave.Active.GetStage(key))
+	if err != nil {
+		return err
+	}
+	NFSave.Active.SetStage(key, characters)
+	return nil
+}
+
+// checkExpression makes sure the character is registered and has the expression
+func checkExpression(character, expression string) error {
+	c, err := NFCharacter.Get(character)
+	if err != nil {
+		return err
+	}
+	_, err = c.Expression(expression)
+	return err
+}
+
+// ShowCharacter shows the character on the stage with the key, if the stage is not on screen
+// the character is added to the active save so it is there when the stage is shown
+func ShowCharacter(key, character, slot, expression, transition string, duration time.Duration) error {
+	if stage, ok := GetStage(key); ok {
+		return stage.ShowCharacter(character, slot, expression, transition, duration)
+	}
+	err := checkExpression(character, expression)
+	if err != nil {
+		return err
+	}
+	return updateSavedStage(key, func(characters []NFSave.StageCharacter) ([]NFSave.StageCharacter, error) {
+		shown := NFSave.StageCharacter{Character: character, Expression: expression, Slot: slot}
+		i := slices.IndexFunc(characters, func(c NFSave.StageCharacter) bool { return c.Character == character })
+		if i >= 0 {
+			characters[i] = shown
+			return characters, nil
+		}
+		return append(characters, shown), nil
+	})
+}
+
+// HideCharacter takes the character off the stage with the key
+func HideCharacter(key, character, transition string, duration time.Duration) error {
+	if stage, ok := GetStage(key); ok {
+		return stage.HideCharacter(character, transition, duration)
+	}
+	return updateSavedStage(key, func(characters []NFSave.StageCharacter) ([]NFSave.StageCharacter, error) {
+		i := slices.IndexFunc(characters, func(c NFSave.StageCharacter) bool { return c.Character == character })
+		if i < 0 {
+			return nil, NFError.NewErrNotFound("character " + character + " is not on the stage")
+		}
+		return slices.Delete(characters, i, i+1), nil
+	})
+}
+
+// SetExpression changes the expression of a character on the stage with the key
+func SetExpression(key, character, expression string) error {
+	if stage, ok := GetStage(key); ok {
+		return stage.SetExpression(character, expression)
+	}
+	err := checkExpression(character, expression)
+	if err != nil {
+		return err
+	}
+	return updateSavedStage(key, func(characters []NFSave.StageCharacter) ([]NFSave.StageCharacter, error) {
+		i := slices.IndexFunc(characters, func(c NFSave.StageCharacter) bool { return c.Character == character })
+		if i < 0 {
+			return nil, NFError.NewErrNotFound("character " + character + " is not on the stage")
+		}
+		characters[i].Expression = expression
+		return characters, nil
+	})
+}
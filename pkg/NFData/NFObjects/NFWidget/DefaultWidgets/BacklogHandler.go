@@ -0,0 +1,93 @@
+package DefaultWidgets
+
+import (
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFFunction"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+	"slices"
+)
+
+// NewBacklog creates a list of the lines in the active save's backlog with the newest line last
+//
+// If allowRollback is true each line has a button that runs the Rollback function for it, onRollback is called after
+// the rollback has run and can be nil
+func NewBacklog(window fyne.Window, allowRollback bool, onRollback func()) fyne.CanvasObject {
+	var entries []NFSave.BacklogEntry
+	if NFSave.Active != nil {
+		entries = slices.Clone(NFSave.Active.GetBacklog())
+	}
+	if len(entries) == 0 {
+		return container.NewCenter(widget.NewLabel("Nothing has been said yet"))
+	}
+	list := widget.NewList(
+		func() int {
+			return len(entries)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			button := widget.NewButton("Rewind", nil)
+			if !allowRollback {
+				button.Hide()
+			}
+			return container.NewBorder(nil, nil, nil, button, label)
+		},
+		func(id widget.ListItemID, object fyne.CanvasObject) {
+			row := object.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			button := row.Objects[1].(*widget.Button)
+			entry := entries[id]
+			if entry.Speaker != "" {
+				label.SetText(entry.Speaker + ": " + entry.Text)
+			} else {
+				label.SetText(entry.Text)
+			}
+			button.OnTapped = func() {
+				_, err := NFFunction.ParseAndRun(window, "Rollback", NFData.NewNFInterfaceMap(NFData.NewKeyVal("Entry", id)))
+				if err != nil {
+					results := NFData.NewNFInterfaceMap()
+					results.Set("Error", fmt.Sprintf("Error rolling back to line %d: %s", id, err.Error()))
+					_, _ = NFFunction.ParseAndRun(window, "Error", results)
+					return
+				}
+				if onRollback != nil {
+					onRollback()
+				}
+			}
+		},
+	)
+	list.ScrollToBottom()
+	return list
+}
+
+// BacklogHandler creates a list of the lines shown in the active save, args["AllowRollback"] adds a button to each line
+// that rolls the save back to it
+func BacklogHandler(window fyne.Window, args *NFData.NFInterfaceMap, w *NFWidget.Widget) (fyne.CanvasObject, error) {
+	allowRollback := true
+	_ = args.Get("AllowRollback", &allowRollback)
+	backlog := NewBacklog(window, allowRollback, nil)
+
+	var hidden = false
+	err := args.Get("Hidden", &hidden)
+	if err == nil && hidden {
+		backlog.Hide()
+	}
+
+	var position = backlog.Position()
+	err = args.Get("Position", &position)
+	if err == nil {
+		backlog.Move(position)
+	}
+
+	var size = backlog.Size()
+	err = args.Get("Size", &size)
+	if err == nil {
+		backlog.Resize(size)
+	}
+	return backlog, nil
+}
@@ -0,0 +1,234 @@
+package DefaultWidgets
+
+import (
+	"errors"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFFunction"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFScene"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// choiceOption is a single option of a ChoiceMenu
+type choiceOption struct {
+	ID       string
+	Text     string
+	Visible  bool
+	Enabled  bool
+	Function interface{}
+}
+
+// parseChoiceOption reads an option map, the Condition and Enabled values can be a condition map, an NFExpression or a bool
+func parseChoiceOption(index int, value interface{}) (choiceOption, error) {
+	optionMap, ok := NFData.AsMap(value)
+	if !ok {
+		return choiceOption{}, NFError.NewErrInvalidArgument("Options", "option "+strconv.Itoa(index)+" must be a map")
+	}
+	option := choiceOption{Visible: true, Enabled: true, Function: optionMap["Function"]}
+	text, err := NFData.Evaluate(optionMap["Text"])
+	if err != nil {
+		return choiceOption{}, err
+	}
+	if text != nil {
+		option.Text = fmt.Sprint(text)
+	}
+	option.ID, _ = optionMap["ID"].(string)
+	if option.ID == "" {
+		option.ID, _ = optionMap["Text"].(string)
+	}
+	if option.ID == "" {
+		option.ID = strconv.Itoa(index)
+	}
+	if condition, ok := optionMap["Condition"]; ok && condition != nil {
+		option.Visible, err = NFData.EvaluateCondition(condition)
+		if err != nil {
+			return choiceOption{}, err
+		}
+	}
+	if enabled, ok := optionMap["Enabled"]; ok && enabled != nil {
+		option.Enabled, err = NFData.EvaluateCondition(enabled)
+		if err != nil {
+			return choiceOption{}, err
+		}
+	}
+	return option, nil
+}
+
+// ChoiceMenuHandler creates a menu of buttons that lets the player pick one of the options in args["Options"]
+//
+// Each option is a map with a "Text", an optional "ID" (the text by default), an optional "Condition" that hides the option
+// when false, an optional "Enabled" condition that disables it when false and a "Function" call that runs when it is picked.
+//
+// Picks are remembered in the active save under NFSave.ChoiceKey(MenuID, ID), so later scenes can check them with the
+// condition {"Location": "Save", "Key": "Chosen.<MenuID>.<ID>", "Operator": "isSet"} or the expression Save.Chosen.<MenuID>.<ID>.
+// After the option's function the widget's OnChosen action is run with the "Menu" and "Option" that were picked.
+// The game is autosaved before the pick is recorded if NFConfig.Game.Autosave has BeforeChoice set.
+//
+// If args["Timeout"] is above 0 the option with the ID in args["Default"] is picked when the time in seconds runs out,
+// or the first option that can be picked if there is no default or the default is hidden or disabled
+func ChoiceMenuHandler(window fyne.Window, args *NFData.NFInterfaceMap, w *NFWidget.Widget) (fyne.CanvasObject, error) {
+	rawOptions, ok := args.UnTypedGet("Options")
+	if !ok {
+		return nil, NFError.NewErrWidgetParse(w.GetName(), w.GetType(), w.GetID(), "missing Options")
+	}
+	optionList, ok := rawOptions.([]interface{})
+	if !ok {
+		return nil, NFError.NewErrWidgetParse(w.GetName(), w.GetType(), w.GetID(), "Options must be a list")
+	}
+	options := make([]choiceOption, 0, len(optionList))
+	for i, value := range optionList {
+		option, err := parseChoiceOption(i, value)
+		if err != nil {
+			return nil, NFError.NewErrWidgetParse(w.GetName(), w.GetType(), w.GetID(), err.Error())
+		}
+		options = append(options, option)
+	}
+
+	menuID := w.GetName()
+	_ = args.Get("MenuID", &menuID)
+
+	menu := container.NewVBox()
+	var prompt string
+	_ = args.Get("Prompt", &prompt)
+	if prompt != "" {
+		menu.Add(widget.NewLabel(prompt))
+	}
+
+	var mu sync.Mutex
+	picked := false
+	done := make(chan struct{})
+	buttons := make([]*widget.Button, 0, len(options))
+	pick := func(option choiceOption) {
+		mu.Lock()
+		if picked {
+			mu.Unlock()
+			return
+		}
+		picked = true
+		mu.Unlock()
+		close(done)
+		//The autosave is taken while the options are still on screen so loading it lets the player choose again
+		NFScene.Autosave(window, NFSave.AutosaveChoice)
+		for _, button := range buttons {
+			button.Disable()
+		}
+		if NFSave.Active != nil {
+			NFSave.Active.RecordChoice(menuID, option.ID)
+		}
+		var err error
+		if option.Function != nil {
+			_, err = NFFunction.RunCall(window, option.Function)
+		}
+		_, actionErr := w.RunAction("OnChosen", window, NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Menu", menuID),
+			NFData.NewKeyVal("Option", option.ID),
+		))
+		if actionErr != nil && !errors.Is(actionErr, NFError.ErrNotImplemented) {
+			err = errors.Join(err, actionErr)
+		}
+		if err != nil {
+			results := NFData.NewNFInterfaceMap()
+			results.Set("Error", fmt.Sprintf("Error running choice %s for menu %s: %s", option.ID, menuID, err.Error()))
+			_, _ = NFFunction.ParseAndRun(window, "Error", results)
+		}
+	}
+
+	for _, option := range options {
+		if !option.Visible {
+			continue
+		}
+		option := option
+		button := widget.NewButton(option.Text, func() { pick(option) })
+		if !option.Enabled {
+			button.Disable()
+		}
+		buttons = append(buttons, button)
+		menu.Add(button)
+	}
+
+	var timeout float64
+	_ = args.Get("Timeout", &timeout)
+	if timeout > 0 {
+		var defaultID string
+		_ = args.Get("Default", &defaultID)
+		//Only an option the player could pick is picked for them, a hidden or disabled default falls back to the first one
+		var defaultOption *choiceOption
+		for i := range options {
+			if options[i].Visible && options[i].Enabled && (defaultID == "" || options[i].ID == defaultID) {
+				defaultOption = &options[i]
+				break
+			}
+		}
+		if defaultOption == nil && defaultID != "" {
+			log.Println("Default option ", defaultID, " of choice menu ", menuID, " can not be picked, using the first option that can")
+			for i := range options {
+				if options[i].Visible && options[i].Enabled {
+					defaultOption = &options[i]
+					break
+				}
+			}
+		}
+		if defaultOption == nil {
+			return nil, NFError.NewErrWidgetParse(w.GetName(), w.GetType(), w.GetID(), "timed choice has no option that can be picked")
+		}
+		bar := widget.NewProgressBar()
+		bar.Max = timeout
+		bar.SetValue(timeout)
+		bar.TextFormatter = func() string {
+			return fmt.Sprintf("%.0fs", bar.Value)
+		}
+		menu.Add(bar)
+		//The timer stops if the scene is changed before the time runs out
+		left := NFScene.SceneLeft()
+		go func() {
+			ticker := time.NewTicker(100 * time.Millisecond)
+			defer ticker.Stop()
+			start := time.Now()
+			for {
+				select {
+				case <-done:
+					return
+				case <-left:
+					return
+				case <-ticker.C:
+					remaining := timeout - time.Since(start).Seconds()
+					if remaining <= 0 {
+						bar.SetValue(0)
+						//The pick runs functions that change scenes and write saves, so it is run with the player's input
+						NFData.RunOnUI(window, func() { pick(*defaultOption) })
+						return
+					}
+					bar.SetValue(remaining)
+				}
+			}
+		}()
+	}
+
+	var hidden = false
+	err := args.Get("Hidden", &hidden)
+	if err == nil && hidden {
+		menu.Hide()
+	}
+
+	var position = menu.Position()
+	err = args.Get("Position", &position)
+	if err == nil {
+		menu.Move(position)
+	}
+
+	var size = menu.Size()
+	err = args.Get("Size", &size)
+	if err == nil {
+		menu.Resize(size)
+	}
+	return menu, nil
+}
@@ -3,12 +3,12 @@ package DefaultWidgets
 import (
 	"errors"
 	"fmt"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFAsset"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFFunction"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFStyling"
 	"log"
-	"os"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -18,6 +18,7 @@ import (
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFCharacter"
 )
 
 //TODO Create an error box widget instead of a function that creates an error box with an option to unwrap the error
@@ -303,7 +304,7 @@ func ButtonHandler(window fyne.Window, args *NFData.NFInterfaceMap, w *NFWidget.
 	return button, nil
 }
 
-// ImageHandler creates an image
+// ImageHandler creates an image from args["Path"], or from the args["Expression"] of the character in args["Character"]
 func ImageHandler(window fyne.Window, args *NFData.NFInterfaceMap, w *NFWidget.Widget) (fyne.CanvasObject, error) {
 	var path string
 	err := args.Get("Path", &path)
@@ -311,15 +312,26 @@ func ImageHandler(window fyne.Window, args *NFData.NFInterfaceMap, w *NFWidget.W
 		return nil, NFError.NewErrWidgetParse(w.GetName(), w.GetType(), w.GetID(), "Error Getting Image Path")
 	}
 
-	// Check if there is an image at the path, or at assets/image/path
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		path = "assets/image/" + path
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			return nil, NFError.NewErrWidgetParse(w.GetName(), w.GetType(), w.GetID(), "Error Getting Image From Path")
+	// If a character is set the path of its expression is used instead
+	var characterID string
+	if args.Get("Character", &characterID) == nil && characterID != "" {
+		var expression string
+		_ = args.Get("Expression", &expression)
+		character, err := NFCharacter.Get(characterID)
+		if err != nil {
+			return nil, NFError.NewErrWidgetParse(w.GetName(), w.GetType(), w.GetID(), err.Error())
+		}
+		path, err = character.Expression(expression)
+		if err != nil {
+			return nil, NFError.NewErrWidgetParse(w.GetName(), w.GetType(), w.GetID(), err.Error())
 		}
 	}
 
-	image := canvas.NewImageFromFile(path)
+	// The image is at the path, or at assets/image/path, and is already decoded if the scene was preloaded
+	image, err := NFAsset.Image(path)
+	if err != nil {
+		return nil, NFError.NewErrWidgetParse(w.GetName(), w.GetType(), w.GetID(), "Error Getting Image From Path")
+	}
 	var hidden = false
 	err = w.Args.Get("Hidden", &hidden)
 	if err == nil {
@@ -109,6 +109,8 @@ func init() {
 			NFData.NewKeyVal("Hidden", false),
 			NFData.NewKeyVal("Position", fyne.NewPos(0, 0)),
 			NFData.NewKeyVal("MinSize", fyne.NewSize(1080, 720)),
+			NFData.NewKeyVal("Character", ""),
+			NFData.NewKeyVal("Expression", ""),
 		),
 	}
 	image.Register(ImageHandler)
@@ -186,4 +188,56 @@ func init() {
 		),
 	}
 	slider.Register(SliderHandler)
+
+	// ChoiceMenuHandler
+	choiceMenu := NFWidget.Widget{
+		Type: "ChoiceMenu",
+		RequiredArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Options", []interface{}{map[string]interface{}{
+				"ID":        "",
+				"Text":      "",
+				"Condition": map[string]interface{}{"Expression": "true"},
+				"Enabled":   map[string]interface{}{"Expression": "true"},
+				"Function":  map[string]interface{}{"Type": "", "Args": map[string]interface{}{}},
+			}}),
+		),
+		OptionalArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("MenuID", ""),
+			NFData.NewKeyVal("Prompt", ""),
+			NFData.NewKeyVal("Timeout", 0.0),
+			NFData.NewKeyVal("Default", ""),
+			NFData.NewKeyVal("Hidden", false),
+			NFData.NewKeyVal("Position", fyne.NewPos(0, 0)),
+			NFData.NewKeyVal("Size", fyne.NewSize(0, 0)),
+		),
+	}
+	choiceMenu.Register(ChoiceMenuHandler)
+
+	// BacklogHandler
+	backlog := NFWidget.Widget{
+		Type:         "Backlog",
+		RequiredArgs: NFData.NewNFInterfaceMap(),
+		OptionalArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("AllowRollback", true),
+			NFData.NewKeyVal("Hidden", false),
+			NFData.NewKeyVal("Position", fyne.NewPos(0, 0)),
+			NFData.NewKeyVal("Size", fyne.NewSize(0, 0)),
+		),
+	}
+	backlog.Register(BacklogHandler)
+
+	// SaveSlotsHandler
+	saveSlots := NFWidget.Widget{
+		Type:         "SaveSlots",
+		RequiredArgs: NFData.NewNFInterfaceMap(),
+		OptionalArgs: NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Mode", SlotModeLoad),
+			NFData.NewKeyVal("Slots", 12),
+			NFData.NewKeyVal("Columns", 3),
+			NFData.NewKeyVal("Hidden", false),
+			NFData.NewKeyVal("Position", fyne.NewPos(0, 0)),
+			NFData.NewKeyVal("Size", fyne.NewSize(0, 0)),
+		),
+	}
+	saveSlots.Register(SaveSlotsHandler)
 }
@@ -0,0 +1,207 @@
+package DefaultWidgets
+
+import (
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFFunction"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+	"image"
+	"strconv"
+	"time"
+)
+
+// The modes of the SaveSlots widget
+const (
+	SlotModeSave = "save"
+	SlotModeLoad = "load"
+)
+
+// FormatPlaytime formats a playtime as hours and minutes
+func FormatPlaytime(playtime time.Duration) string {
+	return fmt.Sprintf("%d:%02d", int(playtime.Hours()), int(playtime.Minutes())%60)
+}
+
+// showSlotError shows an error from a slot action with the Error function
+func showSlotError(window fyne.Window, action, id string, err error) {
+	results := NFData.NewNFInterfaceMap()
+	results.Set("Error", fmt.Sprintf("Error %s slot %s: %s", action, id, err.Error()))
+	_, _ = NFFunction.ParseAndRun(window, "Error", results)
+}
+
+// NewSaveSlots creates a grid of the save slots numbered 1 to count
+//
+// In save mode each slot saves the active save with the thumbnail, which should be captured from the window canvas
+// before the slots are shown. In load mode each slot runs the LoadSlot function.
+// Slots that hold a save can also be renamed and deleted, onDone is called after a save or load and can be nil.
+// Load mode also lists the quick save and autosave slots ahead of the numbered slots
+func NewSaveSlots(window fyne.Window, mode string, count, columns int, thumbnail image.Image, onDone func()) fyne.CanvasObject {
+	grid := container.NewGridWithColumns(max(columns, 1))
+	var build func()
+	slotCard := func(id string) fyne.CanvasObject {
+		info, err := NFSave.GetSlot(id)
+		exists := err == nil
+
+		var preview fyne.CanvasObject
+		if saved, ok := NFSave.SlotThumbnail(id); ok {
+			thumbnailImage := canvas.NewImageFromImage(saved)
+			thumbnailImage.FillMode = canvas.ImageFillContain
+			thumbnailImage.SetMinSize(fyne.NewSize(160, 90))
+			preview = thumbnailImage
+		} else {
+			placeholder := canvas.NewRectangle(theme.InputBackgroundColor())
+			placeholder.SetMinSize(fyne.NewSize(160, 90))
+			preview = placeholder
+		}
+
+		title := "Empty Slot " + id
+		details := widget.NewLabel("")
+		if exists {
+			title = info.Name
+			place := info.Scene
+			if info.Chapter != "" {
+				place = info.Chapter
+			}
+			details.SetText(fmt.Sprintf("%s\n%s\nPlayed %s", place, info.Time.Format("2006-01-02 15:04"), FormatPlaytime(info.Playtime)))
+		}
+
+		buttons := container.NewHBox()
+		switch mode {
+		case SlotModeSave:
+			write := func() {
+				if NFSave.Active == nil {
+					showSlotError(window, "saving to", id, fmt.Errorf("there is no game to save"))
+					return
+				}
+				_, err := NFSave.WriteSlot(id, NFSave.Active, thumbnail)
+				if err != nil {
+					showSlotError(window, "saving to", id, err)
+					return
+				}
+				build()
+				if onDone != nil {
+					onDone()
+				}
+			}
+			buttons.Add(widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+				if !exists {
+					write()
+					return
+				}
+				dialog.ShowConfirm("Overwrite?", "Are you sure you want to overwrite "+info.Name+"?", func(b bool) {
+					if b {
+						write()
+					}
+				}, window)
+			}))
+		default:
+			loadButton := widget.NewButtonWithIcon("Load", theme.FolderOpenIcon(), func() {
+				_, err := NFFunction.ParseAndRun(window, "LoadSlot", NFData.NewNFInterfaceMap(NFData.NewKeyVal("Slot", id)))
+				if err != nil {
+					showSlotError(window, "loading", id, err)
+					return
+				}
+				if onDone != nil {
+					onDone()
+				}
+			})
+			if !exists {
+				loadButton.Disable()
+			}
+			buttons.Add(loadButton)
+		}
+		if exists {
+			buttons.Add(widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), func() {
+				nameEntry := widget.NewEntry()
+				nameEntry.SetText(info.Name)
+				dialog.ShowForm("Rename Slot", "Rename", "Cancel", []*widget.FormItem{widget.NewFormItem("Name", nameEntry)}, func(b bool) {
+					if !b {
+						return
+					}
+					err := NFSave.RenameSlot(id, nameEntry.Text)
+					if err != nil {
+						showSlotError(window, "renaming", id, err)
+						return
+					}
+					build()
+				}, window)
+			}))
+			buttons.Add(widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				dialog.ShowConfirm("Delete?", "Are you sure you want to delete "+info.Name+"?", func(b bool) {
+					if !b {
+						return
+					}
+					err := NFSave.DeleteSlot(id)
+					if err != nil {
+						showSlotError(window, "deleting", id, err)
+						return
+					}
+					build()
+				}, window)
+			}))
+		}
+		return widget.NewCard(title, "", container.NewVBox(preview, details, buttons))
+	}
+	build = func() {
+		grid.RemoveAll()
+		if mode != SlotModeSave {
+			//Quick saves and autosaves can only be loaded, they are only shown once they have been saved to
+			for _, id := range append([]string{NFSave.QuickSlotID}, NFSave.AutosaveSlotIDs()...) {
+				if NFSave.SlotExists(id) {
+					grid.Add(slotCard(id))
+				}
+			}
+		}
+		for i := 1; i <= count; i++ {
+			grid.Add(slotCard(strconv.Itoa(i)))
+		}
+	}
+	build()
+	return container.NewVScroll(grid)
+}
+
+// SaveSlotsHandler creates a grid of save slots, args["Mode"] is either save or load
+//
+// In save mode the thumbnail is captured from the window when the widget is parsed,
+// which is while the window still shows the scene the player is saving from
+func SaveSlotsHandler(window fyne.Window, args *NFData.NFInterfaceMap, w *NFWidget.Widget) (fyne.CanvasObject, error) {
+	mode := SlotModeLoad
+	_ = args.Get("Mode", &mode)
+	count, columns := 12, 3
+	if value, ok := args.UnTypedGet("Slots"); ok {
+		count, _ = NFData.ToInt(value)
+	}
+	if value, ok := args.UnTypedGet("Columns"); ok {
+		columns, _ = NFData.ToInt(value)
+	}
+	var thumbnail image.Image
+	if mode == SlotModeSave && window != nil {
+		thumbnail = window.Canvas().Capture()
+	}
+	slots := NewSaveSlots(window, mode, count, columns, thumbnail, nil)
+
+	var hidden = false
+	err := args.Get("Hidden", &hidden)
+	if err == nil && hidden {
+		slots.Hide()
+	}
+
+	var position = slots.Position()
+	err = args.Get("Position", &position)
+	if err == nil {
+		slots.Move(position)
+	}
+
+	var size = slots.Size()
+	err = args.Get("Size", &size)
+	if err == nil {
+		slots.Resize(size)
+	}
+	return slots, nil
+}
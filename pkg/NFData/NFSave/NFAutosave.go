@@ -0,0 +1,175 @@
+package NFSave
+
+import (
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFConfig"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"image"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// QuickSlotID is the slot QuickSave writes to and QuickLoad reads from
+const QuickSlotID = "quick"
+
+// AutosaveSlotPrefix is the start of the IDs of the autosave slots, they are numbered from 1
+const AutosaveSlotPrefix = "auto-"
+
+// The triggers an autosave can be run for, each one is turned on in NFConfig.Game.Autosave
+const (
+	AutosaveSceneChange = "SceneChange"
+	AutosaveChoice      = "Choice"
+	AutosaveTimer       = "Timer"
+)
+
+// autosaveLock keeps the timer and the other triggers from writing the same slot at once
+var autosaveLock sync.Mutex
+
+// autosaveStop stops the running autosave timer, it is nil when no timer is running
+var autosaveStop chan struct{}
+var autosaveStopLock sync.Mutex
+
+// AutosaveSlotIDs returns the IDs of the autosave slots set in NFConfig.Game.Autosave
+func AutosaveSlotIDs() []string {
+	ids := make([]string, 0, max(NFConfig.Game.Autosave.Slots, 0))
+	for i := 1; i <= NFConfig.Game.Autosave.Slots; i++ {
+		ids = append(ids, AutosaveSlotPrefix+strconv.Itoa(i))
+	}
+	return ids
+}
+
+// nextAutosaveSlot returns the first empty autosave slot, or the one that was saved to longest ago
+func nextAutosaveSlot() string {
+	next := ""
+	var oldest time.Time
+	for _, id := range AutosaveSlotIDs() {
+		info, err := GetSlot(id)
+		if err != nil {
+			return id
+		}
+		if next == "" || info.Time.Before(oldest) {
+			next, oldest = id, info.Time
+		}
+	}
+	return next
+}
+
+// autosaveTriggered returns true if the trigger is turned on in NFConfig.Game.Autosave
+func autosaveTriggered(trigger string) bool {
+	config := NFConfig.Game.Autosave
+	if !config.Enabled() {
+		return false
+	}
+	switch trigger {
+	case AutosaveSceneChange:
+		return config.OnSceneChange
+	case AutosaveChoice:
+		return config.BeforeChoice
+	case AutosaveTimer:
+		return config.Interval > 0
+	}
+	return false
+}
+
+// Autosave saves the active save to the next autosave slot, rotating through the slots so the oldest is overwritten first
+//
+// Nothing is saved and false is returned if there is no active save or the trigger is turned off in NFConfig.Game.Autosave.
+// The thumbnail works the same as in WriteSlot and can be nil
+func Autosave(trigger string, thumbnail image.Image) (bool, error) {
+	if Active == nil || !autosaveTriggered(trigger) {
+		return false, nil
+	}
+	encoded, err := encodeSlot(Active, thumbnail)
+	if err != nil {
+		return false, err
+	}
+	_, err = writeAutosave(encoded)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeAutosave writes the encoded save to the next autosave slot
+func writeAutosave(encoded encodedSlot) (SlotInfo, error) {
+	autosaveLock.Lock()
+	defer autosaveLock.Unlock()
+	id := nextAutosaveSlot()
+	return encoded.write(id, "Autosave "+id[len(AutosaveSlotPrefix):])
+}
+
+// StartAutosaveTimer autosaves every Interval seconds of NFConfig.Game.Autosave until StopAutosaveTimer is called,
+// replacing any timer that is already running
+//
+// The active save is changed by the game's UI, so the timer only schedules each autosave with run,
+// which should run the function on the UI goroutine such as NFData.RunOnUI does, a nil run calls it on the timer's goroutine.
+// The save is encoded there and the slot is written on another goroutine.
+// capture is called for the thumbnail of each autosave and can be nil. Errors are logged as there is no one to return them to
+func StartAutosaveTimer(run func(func()), capture func() image.Image) {
+	StopAutosaveTimer()
+	if !autosaveTriggered(AutosaveTimer) {
+		return
+	}
+	if run == nil {
+		run = func(f func()) { f() }
+	}
+	stop := make(chan struct{})
+	autosaveStopLock.Lock()
+	autosaveStop = stop
+	autosaveStopLock.Unlock()
+	ticker := time.NewTicker(time.Duration(NFConfig.Game.Autosave.Interval * float64(time.Second)))
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				run(func() {
+					if Active == nil || !autosaveTriggered(AutosaveTimer) {
+						return
+					}
+					var thumbnail image.Image
+					if capture != nil {
+						thumbnail = capture()
+					}
+					encoded, err := encodeSlot(Active, thumbnail)
+					if err != nil {
+						log.Println("Error autosaving: ", err)
+						return
+					}
+					go func() {
+						_, err := writeAutosave(encoded)
+						if err != nil {
+							log.Println("Error autosaving: ", err)
+						}
+					}()
+				})
+			}
+		}
+	}()
+}
+
+// StopAutosaveTimer stops the timer started by StartAutosaveTimer, it does nothing if no timer is running
+func StopAutosaveTimer() {
+	autosaveStopLock.Lock()
+	defer autosaveStopLock.Unlock()
+	if autosaveStop != nil {
+		close(autosaveStop)
+		autosaveStop = nil
+	}
+}
+
+// QuickSave saves the active save to the quick save slot, the thumbnail works the same as in WriteSlot and can be nil
+func QuickSave(thumbnail image.Image) (SlotInfo, error) {
+	if Active == nil {
+		return SlotInfo{}, NFError.NewErrNotFound("no active save to quick save")
+	}
+	return writeSlot(QuickSlotID, "Quick Save", Active, thumbnail)
+}
+
+// QuickLoad loads the save in the quick save slot, it does not make it the active save
+func QuickLoad() (*Save, error) {
+	return LoadSlot(QuickSlotID)
+}
@@ -0,0 +1,156 @@
+package NFSave
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"log"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// BacklogLimit is the most lines kept in the backlog of a save, the oldest lines are dropped first
+var BacklogLimit = 100
+
+// BacklogEntry is a line of dialogue that was shown to the player, along with a snapshot of the save when it was shown
+// so that the save can be rolled back to it
+type BacklogEntry struct {
+	Speaker     string                      `json:"Speaker,omitempty"`
+	Text        string                      `json:"Text"`
+	Scene       string                      `json:"Scene"`
+	SceneStack  []string                    `json:"SceneStack,omitempty"`
+	SceneState  map[string]int              `json:"SceneState,omitempty"`
+	SceneStates []map[string]int            `json:"SceneStates,omitempty"`
+	Stages      map[string][]StageCharacter `json:"Stages,omitempty"`
+	Time        time.Time                   `json:"Shown At"`
+	IntData     map[string]int              `json:"IntData,omitempty"`
+	FloatData   map[string]float64          `json:"FloatData,omitempty"`
+	StringData  map[string]string           `json:"StringData,omitempty"`
+	BoolData    map[string]bool             `json:"BoolData,omitempty"`
+	// Sections holds the state of the providers kept for rollback when the line was shown, see KeepForRollback
+	Sections map[string]json.RawMessage `json:"Sections,omitempty"`
+}
+
+// rollbackProviders are the names of the state providers whose state is snapshotted with every backlog line
+var rollbackProviders []string
+
+// KeepForRollback snapshots the state of the named providers with every line recorded in the backlog,
+// so rolling back to the line restores them as well, see RestoreRollbackState.
+// It is meant for small state that changes between lines such as variables, not for the whole game
+func KeepForRollback(names ...string) {
+	stateProvidersLock.Lock()
+	defer stateProvidersLock.Unlock()
+	for _, name := range names {
+		if !slices.Contains(rollbackProviders, name) {
+			rollbackProviders = append(rollbackProviders, name)
+		}
+	}
+}
+
+// captureRollbackState returns the state of the providers kept for rollback, a provider that fails is logged and left out
+func captureRollbackState() map[string]json.RawMessage {
+	stateProvidersLock.RLock()
+	names := slices.Clone(rollbackProviders)
+	stateProvidersLock.RUnlock()
+	sections := make(map[string]json.RawMessage, len(names))
+	for _, name := range names {
+		provider, ok := getStateProvider(name)
+		if !ok {
+			continue
+		}
+		state, err := provider.SaveState()
+		if err == nil {
+			var section []byte
+			section, err = json.Marshal(state)
+			if err == nil {
+				sections[name] = section
+				continue
+			}
+		}
+		log.Println("Error keeping the state of ", name, " for rollback: ", err)
+	}
+	return sections
+}
+
+// RestoreRollbackState gives the providers kept for rollback their state from the entry,
+// this is run once the entry's scene is loaded again so the scene's variables are not replaced by its defaults
+func (entry BacklogEntry) RestoreRollbackState() error {
+	var errs error
+	for name, section := range entry.Sections {
+		provider, ok := getStateProvider(name)
+		if !ok {
+			continue
+		}
+		err := provider.LoadState(section)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("restoring state of %s: %w", name, err))
+		}
+	}
+	return errs
+}
+
+// RecordLine adds a line of dialogue to the backlog along with a snapshot of the save
+//
+// A line that matches the last entry is not recorded again, this happens when a save is resumed on a line that was already shown
+func (s *Save) RecordLine(speaker, text string) {
+	if len(s.Backlog) > 0 {
+		last := s.Backlog[len(s.Backlog)-1]
+		if last.Scene == s.Scene && last.Speaker == speaker && last.Text == text {
+			return
+		}
+	}
+	s.Backlog = append(s.Backlog, BacklogEntry{
+		Speaker:     speaker,
+		Text:        text,
+		Scene:       s.Scene,
+		SceneStack:  slices.Clone(s.SceneStack),
+		SceneState:  maps.Clone(s.SceneState),
+		SceneStates: cloneSceneStates(s.SceneStates),
+		Stages:      cloneStages(s.Stages),
+		Time:        time.Now(),
+		IntData:     maps.Clone(s.IntData),
+		FloatData:   maps.Clone(s.FloatData),
+		StringData:  maps.Clone(s.StringData),
+		BoolData:    maps.Clone(s.BoolData),
+		Sections:    captureRollbackState(),
+	})
+	if BacklogLimit > 0 && len(s.Backlog) > BacklogLimit {
+		s.Backlog = slices.Delete(s.Backlog, 0, len(s.Backlog)-BacklogLimit)
+	}
+}
+
+// GetBacklog returns the lines shown in this save with the oldest first
+func (s *Save) GetBacklog() []BacklogEntry {
+	return s.Backlog
+}
+
+// ClearBacklog forgets all the lines shown in this save
+func (s *Save) ClearBacklog() {
+	s.Backlog = nil
+}
+
+// Rollback restores the save's data, scene, scene stack, scene state and stages to the snapshot taken when the entry was shown
+//
+// The entry and every line after it are removed from the backlog, as the entry is recorded again when its line is shown.
+// The state kept for rollback, such as Global and Scene variables, is restored with RestoreRollbackState once the scene is loaded
+func (s *Save) Rollback(index int) (BacklogEntry, error) {
+	if index < 0 || index >= len(s.Backlog) {
+		return BacklogEntry{}, NFError.NewErrInvalidArgument("index", "there is no backlog entry "+strconv.Itoa(index))
+	}
+	entry := s.Backlog[index]
+	s.Scene = entry.Scene
+	s.SceneStack = slices.Clone(entry.SceneStack)
+	s.SceneState = maps.Clone(entry.SceneState)
+	s.SceneStates = cloneSceneStates(entry.SceneStates)
+	s.Stages = cloneStages(entry.Stages)
+	s.IntData = maps.Clone(entry.IntData)
+	s.FloatData = maps.Clone(entry.FloatData)
+	s.StringData = maps.Clone(entry.StringData)
+	s.BoolData = maps.Clone(entry.BoolData)
+	s.initData()
+	s.Backlog = s.Backlog[:index]
+	return entry, nil
+}
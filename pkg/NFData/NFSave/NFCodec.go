@@ -0,0 +1,138 @@
+package NFSave
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFConfig"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFEncryption"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"io"
+)
+
+// The encodings saves can be written with, saves are read in either encoding no matter which is set
+const (
+	// EncodingJSON writes saves as indented JSON, or compact JSON when they are encrypted, it is the easiest to debug
+	EncodingJSON = "json"
+	// EncodingBinary writes saves as compact JSON compressed with gzip, which keeps saves with long backlogs and histories small
+	EncodingBinary = "binary"
+)
+
+// BinaryMagic starts every save written with EncodingBinary, it is followed by the BinaryVersion and the compressed save
+var BinaryMagic = []byte("NFGB")
+
+// BinaryVersion is the version of the binary layout new saves are written with.
+// The save's JSON is compressed so that migrations see every field of a binary save the same as they do for a JSON save
+const BinaryVersion = 1
+
+// SaveEncoding is the encoding new saves are written with, if it is empty the SaveEncoding of NFConfig.Game is used
+var SaveEncoding = ""
+
+// GetSaveEncoding returns the encoding new saves are actually written with, falling back to the config and then to JSON
+func GetSaveEncoding() string {
+	if SaveEncoding != "" {
+		return SaveEncoding
+	}
+	if NFConfig.Game.SaveEncoding != "" {
+		return NFConfig.Game.SaveEncoding
+	}
+	return EncodingJSON
+}
+
+// SetSaveEncoding is used to set the encoding new saves are written with, it must be EncodingJSON or EncodingBinary
+func SetSaveEncoding(encoding string) error {
+	if encoding != EncodingJSON && encoding != EncodingBinary {
+		return NFError.NewErrInvalidArgument("encoding", "saves can only be encoded as "+EncodingJSON+" or "+EncodingBinary)
+	}
+	SaveEncoding = encoding
+	return nil
+}
+
+// marshal encodes the save with the encoding, JSON is indented unless it is going to be encrypted
+func (s *Save) marshal(encoding string, indent bool) ([]byte, error) {
+	switch encoding {
+	case EncodingJSON, "":
+		if indent {
+			return json.MarshalIndent(s, "", "    ")
+		}
+		return json.Marshal(s)
+	case EncodingBinary:
+		var buf bytes.Buffer
+		buf.Write(BinaryMagic)
+		buf.WriteByte(BinaryVersion)
+		compressor := gzip.NewWriter(&buf)
+		err := json.NewEncoder(compressor).Encode(s)
+		err = errors.Join(err, compressor.Close())
+		if err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, NFError.NewErrInvalidArgument("encoding", "unknown save encoding "+encoding)
+	}
+}
+
+// isBinary returns true if the decrypted bytes of a save were written with EncodingBinary
+func isBinary(saveBytes []byte) bool {
+	return bytes.HasPrefix(saveBytes, BinaryMagic)
+}
+
+// isEncrypted returns true if the unsealed bytes of a save need to be decrypted, which is detected from the bytes
+// rather than from SaveEncryption so saves keep loading when a game turns encryption on or off.
+// Saves encrypted in the current format start with NFEncryption.Magic, those in the legacy format are neither JSON nor binary
+func isEncrypted(saveBytes []byte) bool {
+	return bytes.HasPrefix(saveBytes, NFEncryption.Magic) || (!isBinary(saveBytes) && !json.Valid(saveBytes))
+}
+
+// binaryToJSON decompresses a binary save to its JSON, so migrations and decoding work the same for both encodings
+func binaryToJSON(saveBytes []byte) ([]byte, error) {
+	if len(saveBytes) <= len(BinaryMagic) {
+		return nil, NFError.NewErrSaveCorrupt("the binary save is empty")
+	}
+	version := saveBytes[len(BinaryMagic)]
+	if version != BinaryVersion {
+		return nil, NFError.NewErrSaveCorrupt("unknown binary save version")
+	}
+	decompressor, err := gzip.NewReader(bytes.NewReader(saveBytes[len(BinaryMagic)+1:]))
+	if err != nil {
+		return nil, NFError.NewErrSaveCorrupt(err.Error())
+	}
+	//Reading to the end checks the gzip checksum
+	jsonBytes, err := io.ReadAll(decompressor)
+	if err != nil {
+		return nil, NFError.NewErrSaveCorrupt(err.Error())
+	}
+	return bytes.TrimSpace(jsonBytes), nil
+}
+
+// DumpJSON turns the bytes of any save file in to indented JSON, the key is used if the save is encrypted
+//
+// The save is shown as it was written, it is not migrated. This is meant for debugging, see cmd/savedump
+func DumpJSON(fileBytes []byte, key string) ([]byte, error) {
+	saveBytes, err := unseal(fileBytes)
+	if err != nil {
+		return nil, err
+	}
+	if isEncrypted(saveBytes) {
+		if key == "" {
+			key = GetSaveEncryptionKey()
+		}
+		saveBytes, err = NFEncryption.Decrypt(saveBytes, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if isBinary(saveBytes) {
+		saveBytes, err = binaryToJSON(saveBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var indented bytes.Buffer
+	err = json.Indent(&indented, saveBytes, "", "    ")
+	if err != nil {
+		return nil, NFError.NewErrSaveCorrupt(err.Error())
+	}
+	return indented.Bytes(), nil
+}
@@ -0,0 +1,228 @@
+package NFSave
+
+import (
+	"errors"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// FyneStorage keeps saves under a Fyne URI, which lets games save inside the sandbox mobile platforms give each app
+type FyneStorage struct {
+	Root fyne.URI
+}
+
+// NewFyneStorage creates a storage under the root URI
+func NewFyneStorage(root fyne.URI) *FyneStorage {
+	return &FyneStorage{Root: root}
+}
+
+// NewAppStorage creates a storage in a saves folder of the app's own storage, this is the place to keep saves on mobile
+func NewAppStorage(app fyne.App) (*FyneStorage, error) {
+	root, err := storage.Child(app.Storage().RootURI(), "saves")
+	if err != nil {
+		return nil, err
+	}
+	return NewFyneStorage(root), nil
+}
+
+// uri returns the URI of the name
+func (f *FyneStorage) uri(name string) (fyne.URI, error) {
+	uri := f.Root
+	for _, part := range strings.Split(strings.Trim(path.Clean("/"+name), "/"), "/") {
+		if part == "" {
+			continue
+		}
+		var err error
+		uri, err = storage.Child(uri, part)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return uri, nil
+}
+
+// mkdirAll creates the folder and every folder it is in
+func (f *FyneStorage) mkdirAll(uri fyne.URI) error {
+	ok, err := storage.Exists(uri)
+	if err != nil || ok {
+		return err
+	}
+	parent, err := storage.Parent(uri)
+	if err == nil {
+		err = f.mkdirAll(parent)
+		if err != nil {
+			return err
+		}
+	}
+	return storage.CreateListable(uri)
+}
+
+// entry describes the URI, the time it was written is only known for file URIs
+func (f *FyneStorage) entry(uri fyne.URI) StorageEntry {
+	entry := StorageEntry{Name: uri.Name()}
+	entry.IsDir, _ = storage.CanList(uri)
+	if uri.Scheme() == "file" {
+		if info, err := os.Stat(uri.Path()); err == nil {
+			entry.Size, entry.ModTime = info.Size(), info.ModTime()
+		}
+	}
+	return entry
+}
+
+func (f *FyneStorage) Read(name string) ([]byte, error) {
+	uri, err := f.uri(name)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := storage.Exists(uri); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, notExist("read", name)
+	}
+	reader, err := storage.Reader(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func (f *FyneStorage) Write(name string, data []byte) error {
+	uri, err := f.uri(name)
+	if err != nil {
+		return err
+	}
+	parent, err := storage.Parent(uri)
+	if err != nil {
+		return err
+	}
+	err = f.mkdirAll(parent)
+	if err != nil {
+		return err
+	}
+	//The data is written next to the file and moved over it, repositories that can not move fall back to copying in Rename
+	tempName := path.Join(path.Dir(name), "."+path.Base(name)+".tmp")
+	tempURI, err := f.uri(tempName)
+	if err != nil {
+		return err
+	}
+	err = f.write(tempURI, data)
+	if err != nil {
+		_ = storage.Delete(tempURI)
+		return err
+	}
+	return f.Rename(tempName, name)
+}
+
+// write writes the data straight to the URI
+func (f *FyneStorage) write(uri fyne.URI, data []byte) error {
+	writer, err := storage.Writer(uri)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return errors.Join(err, writer.Close())
+}
+
+func (f *FyneStorage) Remove(name string) error {
+	uri, err := f.uri(name)
+	if err != nil {
+		return err
+	}
+	return f.remove(uri)
+}
+
+// remove deletes the URI and everything inside it
+func (f *FyneStorage) remove(uri fyne.URI) error {
+	if ok, err := storage.Exists(uri); err != nil || !ok {
+		return err
+	}
+	if listable, _ := storage.CanList(uri); listable {
+		children, err := storage.List(uri)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			err = f.remove(child)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return storage.Delete(uri)
+}
+
+func (f *FyneStorage) Rename(oldName, newName string) error {
+	oldURI, err := f.uri(oldName)
+	if err != nil {
+		return err
+	}
+	newURI, err := f.uri(newName)
+	if err != nil {
+		return err
+	}
+	if ok, err := storage.Exists(oldURI); err != nil {
+		return err
+	} else if !ok {
+		return notExist("rename", oldName)
+	}
+	parent, err := storage.Parent(newURI)
+	if err != nil {
+		return err
+	}
+	err = f.mkdirAll(parent)
+	if err != nil {
+		return err
+	}
+	if err = storage.Move(oldURI, newURI); err == nil {
+		return nil
+	}
+	//Not every repository can move, so the file is copied and the old one deleted
+	data, err := f.Read(oldName)
+	if err != nil {
+		return err
+	}
+	err = f.write(newURI, data)
+	if err != nil {
+		return err
+	}
+	return storage.Delete(oldURI)
+}
+
+func (f *FyneStorage) Stat(name string) (StorageEntry, error) {
+	uri, err := f.uri(name)
+	if err != nil {
+		return StorageEntry{}, err
+	}
+	if ok, err := storage.Exists(uri); err != nil {
+		return StorageEntry{}, err
+	} else if !ok {
+		return StorageEntry{}, notExist("stat", name)
+	}
+	return f.entry(uri), nil
+}
+
+func (f *FyneStorage) List(dir string) ([]StorageEntry, error) {
+	uri, err := f.uri(dir)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := storage.Exists(uri); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, notExist("list", dir)
+	}
+	children, err := storage.List(uri)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]StorageEntry, 0, len(children))
+	for _, child := range children {
+		entries = append(entries, f.entry(child))
+	}
+	return entries, nil
+}
@@ -0,0 +1,138 @@
+package NFSave
+
+import (
+	"errors"
+	"fmt"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// HistoryFolder is the folder inside a save's folder that holds its snapshots
+const HistoryFolder = "history"
+
+// HistoryExtension is the extension of snapshot files, they can be loaded with Load like a save file
+const HistoryExtension = Extension + "history"
+
+// HistoryLimit is the most snapshots kept for each save, the oldest are deleted first, 0 keeps every snapshot
+var HistoryLimit = 20
+
+var snapshotFile = regexp.MustCompile(`^save-(\d+)` + regexp.QuoteMeta(HistoryExtension) + `$`)
+
+// Snapshot is an earlier version of a named save, kept when the save was overwritten
+type Snapshot struct {
+	// Save is the name of the save the snapshot was taken of
+	Save string
+	// Number counts up from 1 with each snapshot of the save and is never reused, even after old snapshots are deleted
+	Number int
+	// Path is the name of the snapshot in the Store
+	Path string
+	// Time is when the snapshot's version of the save was written, it is zero if the Store can not tell
+	Time time.Time
+}
+
+// savePath returns the name in the Store of a named save's file
+func savePath(name string) string {
+	return path.Join(name, "save"+Extension)
+}
+
+// snapshotPath returns the name in the Store of a named save's snapshot
+func snapshotPath(name string, number int) string {
+	return path.Join(name, HistoryFolder, fmt.Sprintf("save-%06d%s", number, HistoryExtension))
+}
+
+// ListSnapshots returns the snapshots of the named save with the oldest first
+func ListSnapshots(name string) ([]Snapshot, error) {
+	entries, err := Store.List(path.Join(name, HistoryFolder))
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Snapshot{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	snapshots := make([]Snapshot, 0, len(entries))
+	for _, entry := range entries {
+		match := snapshotFile.FindStringSubmatch(entry.Name)
+		if entry.IsDir || match == nil {
+			continue
+		}
+		number, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, Snapshot{
+			Save:   name,
+			Number: number,
+			Path:   path.Join(name, HistoryFolder, entry.Name),
+			Time:   entry.ModTime,
+		})
+	}
+	slices.SortFunc(snapshots, func(a, b Snapshot) int {
+		return a.Number - b.Number
+	})
+	return snapshots, nil
+}
+
+// LoadSnapshot loads a snapshot of the named save, it does not make it the active save
+func LoadSnapshot(name string, number int) (*Save, error) {
+	fileBytes, err := Store.Read(snapshotPath(name, number))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, NFError.NewErrNotFound("no snapshot " + strconv.Itoa(number) + " of save " + name)
+	} else if err != nil {
+		return nil, err
+	}
+	return decode(fileBytes)
+}
+
+// RestoreSnapshot makes a snapshot of the named save the active save
+//
+// The save file is left as it is until the restored save is saved, which snapshots it like any other save
+func RestoreSnapshot(name string, number int) error {
+	save, err := LoadSnapshot(name, number)
+	if err != nil {
+		return err
+	}
+	save.Name = name
+	Active = save
+	return nil
+}
+
+// SlotHistoryLimit is the most snapshots kept in each save slot, slots always keep at least one
+// so a slot whose save was cut short while it was written can still be loaded, see LoadSlot
+var SlotHistoryLimit = 2
+
+// snapshot moves the named save's file in to its history as the next snapshot and deletes the snapshots past the limit,
+// a limit of 0 keeps every snapshot
+//
+// The file is moved without being decoded, so encrypted saves stay encrypted and keep the time they were written.
+// Save slots are kept the same way, their name is the slot's folder
+func snapshot(name string, limit int) error {
+	if ok, err := exists(savePath(name)); err != nil || !ok {
+		return err
+	}
+	snapshots, err := ListSnapshots(name)
+	if err != nil {
+		return err
+	}
+	next := 1
+	if len(snapshots) > 0 {
+		next = snapshots[len(snapshots)-1].Number + 1
+	}
+	err = Store.Rename(savePath(name), snapshotPath(name, next))
+	if err != nil {
+		return err
+	}
+	if limit <= 0 || len(snapshots)+1 <= limit {
+		return nil
+	}
+	for _, old := range snapshots[:len(snapshots)+1-limit] {
+		err = Store.Remove(old.Path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package NFSave
+
+import (
+	"errors"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"reflect"
+	"testing"
+)
+
+// saveScenes writes a save with the name once for each scene, so each earlier write becomes a snapshot
+func saveScenes(t *testing.T, name string, scenes ...string) {
+	t.Helper()
+	for _, scene := range scenes {
+		save, _ := New(scene)
+		save.SetSaveName(name)
+		if err := save.Save(); err != nil {
+			t.Fatalf("Save of scene %s returned an error: %v", scene, err)
+		}
+	}
+}
+
+// snapshotNumbers returns the numbers of the snapshots of the save with the oldest first
+func snapshotNumbers(t *testing.T, name string) []int {
+	t.Helper()
+	snapshots, err := ListSnapshots(name)
+	if err != nil {
+		t.Fatalf("ListSnapshots(%q) returned an error: %v", name, err)
+	}
+	numbers := make([]int, 0, len(snapshots))
+	for _, s := range snapshots {
+		numbers = append(numbers, s.Number)
+	}
+	return numbers
+}
+
+func TestSnapshotRetention(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		writes []string
+		want   []int
+	}{
+		{"first write has no snapshot", 3, []string{"One"}, []int{}},
+		{"under the limit", 3, []string{"One", "Two", "Three"}, []int{1, 2}},
+		{"at the limit", 3, []string{"One", "Two", "Three", "Four"}, []int{1, 2, 3}},
+		{"past the limit drops the oldest", 3, []string{"One", "Two", "Three", "Four", "Five", "Six"}, []int{3, 4, 5}},
+		{"limit of one", 1, []string{"One", "Two", "Three"}, []int{2}},
+		{"no limit", 0, []string{"One", "Two", "Three", "Four", "Five"}, []int{1, 2, 3, 4}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStorage(t)
+			HistoryLimit = test.limit
+			saveScenes(t, "Game", test.writes...)
+			if got := snapshotNumbers(t, "Game"); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("snapshots after %d writes with a limit of %d = %v, want %v", len(test.writes), test.limit, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotKeepsOlderVersions(t *testing.T) {
+	useMemoryStorage(t)
+	HistoryLimit = 2
+	saveScenes(t, "Game", "One", "Two", "Three", "Four")
+	tests := []struct {
+		number int
+		scene  string
+	}{
+		{2, "Two"},
+		{3, "Three"},
+	}
+	for _, test := range tests {
+		save, err := LoadSnapshot("Game", test.number)
+		if err != nil || save.Scene != test.scene {
+			t.Errorf("LoadSnapshot(%d) = %v, %v, want the save at scene %s", test.number, save, err, test.scene)
+		}
+	}
+	if _, err := LoadSnapshot("Game", 1); !errors.Is(err, NFError.ErrNotFound) {
+		t.Errorf("LoadSnapshot of a dropped snapshot returned %v, want an ErrNotFound", err)
+	}
+	save, err := LoadNamed("Game")
+	if err != nil || save.Scene != "Four" {
+		t.Errorf("LoadNamed = %v, %v, want the save at scene Four", save, err)
+	}
+}
+
+func TestSnapshotNumbersAreNotReused(t *testing.T) {
+	memory := useMemoryStorage(t)
+	HistoryLimit = 0
+	saveScenes(t, "Game", "One", "Two", "Three")
+	_ = memory.Remove(snapshotPath("Game", 1))
+	saveScenes(t, "Game", "Four")
+	if got, want := snapshotNumbers(t, "Game"), []int{2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("snapshots = %v, want %v", got, want)
+	}
+}
+
+func TestSnapshotWithoutHistory(t *testing.T) {
+	useMemoryStorage(t)
+	SaveHistory = false
+	saveScenes(t, "Game", "One", "Two")
+	if got := snapshotNumbers(t, "Game"); len(got) != 0 {
+		t.Errorf("snapshots with SaveHistory off = %v, want none", got)
+	}
+}
+
+func TestSlotRetention(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  []int
+	}{
+		{"past the limit drops the oldest", 2, []int{2, 3}},
+		{"a limit of 0 still keeps one", 0, []int{3}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStorage(t)
+			SlotHistoryLimit = test.limit
+			for _, scene := range []string{"One", "Two", "Three", "Four"} {
+				save, _ := New(scene)
+				if _, err := WriteSlot("1", save, nil); err != nil {
+					t.Fatalf("WriteSlot of scene %s returned an error: %v", scene, err)
+				}
+			}
+			if got := snapshotNumbers(t, SlotsFolder+"/1"); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("slot snapshots with a limit of %d = %v, want %v", test.limit, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package NFSave
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"io/fs"
+	"log"
+	"strconv"
+)
+
+// IntegrityMagic starts the first line of every save file written with a checksum
+//
+// The line is the magic, the version of the line and the hex SHA-256 of the rest of the file, such as "NFSV1 9f86d0...\n",
+// the rest of the file is the save as it was before checksums, so unencrypted saves can still be read as JSON
+const IntegrityMagic = "NFSV"
+
+// IntegrityVersion is the version of the checksum line new saves are written with
+const IntegrityVersion = 1
+
+// seal adds the checksum line to the bytes of a save
+func seal(saveBytes []byte) []byte {
+	sum := sha256.Sum256(saveBytes)
+	header := IntegrityMagic + strconv.Itoa(IntegrityVersion) + " " + hex.EncodeToString(sum[:]) + "\n"
+	return append([]byte(header), saveBytes...)
+}
+
+// unseal checks the checksum line of the bytes of a save and returns the save without it
+//
+// Saves written before checksums have no line and are returned as they are,
+// saves that were cut short or changed fail with NFError.ErrSaveCorrupt
+func unseal(fileBytes []byte) ([]byte, error) {
+	if !bytes.HasPrefix(fileBytes, []byte(IntegrityMagic)) {
+		return fileBytes, nil
+	}
+	header, saveBytes, found := bytes.Cut(fileBytes, []byte("\n"))
+	if !found {
+		return nil, NFError.NewErrSaveCorrupt("the checksum line is not finished, the file was cut short")
+	}
+	version, checksum, found := bytes.Cut(header[len(IntegrityMagic):], []byte(" "))
+	if !found {
+		return nil, NFError.NewErrSaveCorrupt("the checksum line is malformed")
+	}
+	if string(version) != strconv.Itoa(IntegrityVersion) {
+		return nil, NFError.NewErrSaveCorrupt("unknown checksum version " + string(version))
+	}
+	expected, err := hex.DecodeString(string(checksum))
+	if err != nil || len(expected) != sha256.Size {
+		return nil, NFError.NewErrSaveCorrupt("the checksum is malformed")
+	}
+	sum := sha256.Sum256(saveBytes)
+	if !bytes.Equal(sum[:], expected) {
+		return nil, NFError.NewErrSaveCorrupt("the checksum does not match, the file was cut short or changed")
+	}
+	return saveBytes, nil
+}
+
+// corrupt turns errors from reading the contents of a save in to NFError.ErrSaveCorrupt,
+// this catches saves written before checksums that were cut short
+func corrupt(err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return NFError.NewErrSaveCorrupt(err.Error())
+	}
+	return err
+}
+
+// recoverable returns true if a save that failed to load with the error may load from one of its snapshots
+func recoverable(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, NFError.ErrSaveCorrupt) || errors.Is(err, NFError.ErrDecryption)
+}
+
+// recoverSave loads the newest snapshot of the named save that is valid, it is used when the save itself can not be loaded.
+// The name is the save's folder in the Store, which for a save slot is the slot's folder
+//
+// The error wraps NFError.ErrSaveRecovered and says which snapshot was used, if no snapshot loads the cause is returned
+func recoverSave(name string, cause error) (*Save, error) {
+	snapshots, err := ListSnapshots(name)
+	if err != nil {
+		return nil, errors.Join(cause, err)
+	}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		save, err := LoadSnapshot(name, snapshots[i].Number)
+		if err != nil {
+			log.Println("Error loading snapshot "+strconv.Itoa(snapshots[i].Number)+" of save "+name+": ", err)
+			continue
+		}
+		log.Println("Recovered save "+name+" from snapshot "+strconv.Itoa(snapshots[i].Number)+": ", cause)
+		return save, NFError.NewErrSaveRecovered(name, snapshots[i].Number, cause)
+	}
+	return nil, cause
+}
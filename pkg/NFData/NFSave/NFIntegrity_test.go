@@ -0,0 +1,137 @@
+package NFSave
+
+import (
+	"bytes"
+	"errors"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSealUnseal(t *testing.T) {
+	saveBytes := []byte(`{"Scene":"One"}`)
+	sealed := seal(saveBytes)
+	if !bytes.HasPrefix(sealed, []byte(IntegrityMagic+"1 ")) {
+		t.Fatalf("seal = %q, want it to start with the checksum line", sealed)
+	}
+	unsealed, err := unseal(sealed)
+	if err != nil || !bytes.Equal(unsealed, saveBytes) {
+		t.Errorf("unseal = %q, %v, want %q", unsealed, err, saveBytes)
+	}
+	if unsealed, err := unseal(saveBytes); err != nil || !bytes.Equal(unsealed, saveBytes) {
+		t.Errorf("unseal of a save written before checksums = %q, %v, want it as it is", unsealed, err)
+	}
+	header, _, _ := bytes.Cut(sealed, []byte("\n"))
+	tests := []struct {
+		name   string
+		file   []byte
+		reason string
+	}{
+		{"cut short in the save", sealed[:len(sealed)-3], "the checksum does not match"},
+		{"cut short in the checksum line", sealed[:len(header)-4], "the checksum line is not finished"},
+		{"changed", bytes.Replace(sealed, []byte("One"), []byte("Two"), 1), "the checksum does not match"},
+		{"no checksum", []byte(IntegrityMagic + "1\n{}"), "the checksum line is malformed"},
+		{"unknown version", append([]byte(IntegrityMagic+"9"), sealed[len(IntegrityMagic)+1:]...), "unknown checksum version 9"},
+		{"malformed checksum", []byte(IntegrityMagic + "1 xyz\n{}"), "the checksum is malformed"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := unseal(test.file)
+			if !errors.Is(err, NFError.ErrSaveCorrupt) || !strings.Contains(err.Error(), test.reason) {
+				t.Errorf("unseal returned %v, want an ErrSaveCorrupt for %q", err, test.reason)
+			}
+		})
+	}
+}
+
+// truncate cuts the last bytes off the file in the storage
+func truncate(t *testing.T, storage Storage, name string, cut int) {
+	t.Helper()
+	fileBytes, err := storage.Read(name)
+	if err != nil {
+		t.Fatalf("Read(%q) returned an error: %v", name, err)
+	}
+	if err := storage.Write(name, fileBytes[:len(fileBytes)-cut]); err != nil {
+		t.Fatalf("Write(%q) returned an error: %v", name, err)
+	}
+}
+
+func TestLoadNamedRecovers(t *testing.T) {
+	tests := []struct {
+		name       string
+		encryption bool
+		encoding   string
+		// damage breaks the save and the snapshots that should be skipped
+		damage   func(t *testing.T, storage Storage)
+		scene    string
+		snapshot int
+	}{
+		{"truncated save", false, "", func(t *testing.T, storage Storage) {
+			truncate(t, storage, savePath("Game"), 10)
+		}, "Three", 2},
+		{"truncated encrypted save", true, "", func(t *testing.T, storage Storage) {
+			truncate(t, storage, savePath("Game"), 10)
+		}, "Three", 2},
+		{"truncated binary save", false, EncodingBinary, func(t *testing.T, storage Storage) {
+			truncate(t, storage, savePath("Game"), 10)
+		}, "Three", 2},
+		{"save cut short in its checksum line", false, "", func(t *testing.T, storage Storage) {
+			fileBytes, _ := storage.Read(savePath("Game"))
+			_ = storage.Write(savePath("Game"), fileBytes[:len(IntegrityMagic)+3])
+		}, "Three", 2},
+		{"missing save", false, "", func(t *testing.T, storage Storage) {
+			_ = storage.Remove(savePath("Game"))
+		}, "Three", 2},
+		{"newest snapshot is truncated too", false, "", func(t *testing.T, storage Storage) {
+			truncate(t, storage, savePath("Game"), 10)
+			truncate(t, storage, snapshotPath("Game", 2), 1)
+		}, "Two", 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			memory := useMemoryStorage(t)
+			SaveEncryption, SaveEncoding = test.encryption, test.encoding
+			saveScenes(t, "Game", "Two", "Three", "Four")
+			test.damage(t, memory)
+			save, err := LoadNamed("Game")
+			if !errors.Is(err, NFError.ErrSaveRecovered) {
+				t.Fatalf("LoadNamed returned %v, want an ErrSaveRecovered", err)
+			}
+			if save == nil || save.Scene != test.scene || save.Name != "Game" {
+				t.Fatalf("LoadNamed recovered %+v, want the save named Game at scene %s", save, test.scene)
+			}
+			if want := "snapshot " + strconv.Itoa(test.snapshot); !strings.Contains(err.Error(), want) {
+				t.Errorf("LoadNamed returned %q, want it to say it used %s", err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestLoadNamedDoesNotRecover(t *testing.T) {
+	useMemoryStorage(t)
+	saveScenes(t, "Game", "One")
+	truncate(t, Store, savePath("Game"), 10)
+	save, err := LoadNamed("Game")
+	if save != nil || !errors.Is(err, NFError.ErrSaveCorrupt) || errors.Is(err, NFError.ErrSaveRecovered) {
+		t.Errorf("LoadNamed of a corrupt save with no snapshots = %v, %v, want an ErrSaveCorrupt", save, err)
+	}
+	if _, err := LoadNamed("Missing"); !errors.Is(err, NFError.ErrNotFound) {
+		t.Errorf("LoadNamed of a missing save returned %v, want an ErrNotFound", err)
+	}
+}
+
+func TestLoadSlotRecovers(t *testing.T) {
+	useMemoryStorage(t)
+	for _, scene := range []string{"One", "Two", "Three"} {
+		save, _ := New(scene)
+		if _, err := WriteSlot("1", save, nil); err != nil {
+			t.Fatalf("WriteSlot of scene %s returned an error: %v", scene, err)
+		}
+	}
+	truncate(t, Store, slotPath("1", slotSaveFile), 10)
+	save, err := LoadSlot("1")
+	if !errors.Is(err, NFError.ErrSaveRecovered) || save == nil || save.Scene != "Two" {
+		t.Errorf("LoadSlot of a truncated slot = %+v, %v, want the save at scene Two and an ErrSaveRecovered", save, err)
+	}
+}
@@ -0,0 +1,92 @@
+package NFSave
+
+import (
+	"encoding/json"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFLog"
+	"math"
+	"strconv"
+	"sync"
+)
+
+// CurrentSchemaVersion is the schema version new saves are written with.
+//
+// When a release changes its variables in a way older saves do not match, raise it
+// and register a migration from the old version so those saves keep loading
+var CurrentSchemaVersion = 1
+
+// Migration upgrades a decoded save from one schema version to the next.
+//
+// The save is the JSON object of the save file, so keys can be renamed and values converted freely,
+// the typed data is under "IntData", "FloatData", "StringData" and "BoolData".
+// The SchemaVersion key is updated after the migration returns.
+// Binary saves are decoded in to the current Save before they are migrated, so fields it no longer has are already gone
+type Migration func(save map[string]interface{}) error
+
+var migrations = map[int]Migration{}
+var migrationsLock sync.RWMutex
+
+func init() {
+	//Saves written before schema versions existed have no version and match version 1
+	_ = RegisterMigration(0, func(map[string]interface{}) error { return nil })
+}
+
+// RegisterMigration registers the migration that upgrades saves from the version to the version after it
+func RegisterMigration(from int, migration Migration) error {
+	migrationsLock.Lock()
+	defer migrationsLock.Unlock()
+	if _, ok := migrations[from]; ok {
+		return NFError.NewErrKeyAlreadyExists("migration from schema version " + strconv.Itoa(from))
+	}
+	migrations[from] = migration
+	return nil
+}
+
+// schemaVersion reads the version of a decoded save, saves without one are version 0
+func schemaVersion(save map[string]interface{}) (int, error) {
+	value, ok := save["SchemaVersion"]
+	if !ok || value == nil {
+		return 0, nil
+	}
+	version, ok := value.(float64)
+	if !ok || version != math.Trunc(version) {
+		return 0, NFError.NewErrInvalidArgument("SchemaVersion", "the schema version must be a whole number")
+	}
+	return int(version), nil
+}
+
+// migrate runs the registered migrations on the JSON of a save until it is at CurrentSchemaVersion
+//
+// Saves from a newer schema version than the game supports are refused with NFError.ErrSaveTooNew
+func migrate(saveBytes []byte) ([]byte, error) {
+	save := map[string]interface{}{}
+	err := json.Unmarshal(saveBytes, &save)
+	if err != nil {
+		return nil, err
+	}
+	version, err := schemaVersion(save)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentSchemaVersion {
+		return nil, NFError.NewErrSaveTooNew(version, CurrentSchemaVersion)
+	}
+	if version == CurrentSchemaVersion {
+		return saveBytes, nil
+	}
+	migrationsLock.RLock()
+	defer migrationsLock.RUnlock()
+	for ; version < CurrentSchemaVersion; version++ {
+		migration, ok := migrations[version]
+		if !ok {
+			return nil, NFError.NewErrSaveMigration(version, "no migration is registered")
+		}
+		NFLog.Printf("Migrating save %v from schema version %d to %d", save["Name"], version, version+1)
+		err = migration(save)
+		if err != nil {
+			return nil, NFError.NewErrSaveMigration(version, err.Error())
+		}
+		save["SchemaVersion"] = version + 1
+	}
+	return json.Marshal(save)
+}
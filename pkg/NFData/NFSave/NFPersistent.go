@@ -0,0 +1,235 @@
+package NFSave
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFEncryption"
+	"io/fs"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PersistentFile is the name in the Store of the data shared by every save
+const PersistentFile = "persistent" + Extension
+
+// The prefixes of the bool data keys used for unlocks and endings
+const (
+	UnlockPrefix = "Unlocked."
+	EndingPrefix = "Ending."
+)
+
+// Persistent holds the data shared by every save, such as endings reached, gallery unlocks and lines already read
+//
+// It is loaded with LoadPersistent when the game starts and written to the Store PersistentSaveDelay after it changes,
+// so a run of changes such as every line of a scene being marked read is written once
+var Persistent = NewPersistentData()
+
+// PersistentSaveDelay is how long persistent data waits after a change before it is written,
+// changes made while it waits are written with it. Call Flush to write it straight away, such as when the game quits
+var PersistentSaveDelay = 2 * time.Second
+
+// PersistentData is the data kept across every save of a game, it has the same typed accessors as Save
+type PersistentData struct {
+	Data
+	// ReadLines holds the lines of dialogue the player has seen in any save, see LineKey
+	ReadLines map[string]bool `json:"ReadLines,omitempty"`
+
+	//lock guards the data maps and ReadLines
+	lock sync.RWMutex
+	//writeLock keeps two writes of the file from running at once
+	writeLock sync.Mutex
+	//flushLock guards dirty and flushTimer, dirty is set when there are changes that have not been written
+	flushLock  sync.Mutex
+	dirty      bool
+	flushTimer *time.Timer
+}
+
+// NewPersistentData creates empty persistent data that saves itself to the Store after it changes
+func NewPersistentData() *PersistentData {
+	p := &PersistentData{Data: NewData(), ReadLines: map[string]bool{}}
+	p.onChange = p.markDirty
+	p.Data.lock = &p.lock
+	return p
+}
+
+// LoadPersistent replaces Persistent with the data in the Store, a game without persistent data yet starts empty
+func LoadPersistent() error {
+	//Changes made before loading are written first so loading reads them back, as they would have been without the delay
+	err := Persistent.Flush()
+	if err != nil {
+		log.Println("Error saving persistent data: ", err)
+	}
+	p := NewPersistentData()
+	fileBytes, err := Store.Read(PersistentFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		Persistent = p
+		return nil
+	} else if err != nil {
+		return err
+	}
+	fileBytes, err = unseal(fileBytes)
+	if err != nil {
+		return err
+	}
+	if isEncrypted(fileBytes) {
+		fileBytes, err = decrypt(fileBytes)
+		if err != nil {
+			return err
+		}
+	}
+	err = json.Unmarshal(fileBytes, p)
+	if err != nil {
+		return err
+	}
+	p.initData()
+	if p.ReadLines == nil {
+		p.ReadLines = map[string]bool{}
+	}
+	Persistent = p
+	return nil
+}
+
+// Save writes the persistent data to the Store, this happens on its own a moment after a value changes, see PersistentSaveDelay
+func (p *PersistentData) Save() error {
+	p.writeLock.Lock()
+	defer p.writeLock.Unlock()
+	p.lock.RLock()
+	fileBytes, err := json.MarshalIndent(p, "", "    ")
+	p.lock.RUnlock()
+	if err != nil {
+		return err
+	}
+	if SaveEncryption {
+		fileBytes, err = NFEncryption.Encrypt(fileBytes, GetSaveEncryptionKey())
+		if err != nil {
+			return err
+		}
+	}
+	return Store.Write(PersistentFile, seal(fileBytes))
+}
+
+// Flush writes the persistent data now if it has changes waiting for PersistentSaveDelay, it does nothing otherwise
+func (p *PersistentData) Flush() error {
+	p.flushLock.Lock()
+	dirty := p.dirty
+	p.dirty = false
+	if p.flushTimer != nil {
+		p.flushTimer.Stop()
+		p.flushTimer = nil
+	}
+	p.flushLock.Unlock()
+	if !dirty {
+		return nil
+	}
+	err := p.Save()
+	if err != nil {
+		//The changes are still not written, so the next change or Flush tries again
+		p.flushLock.Lock()
+		p.dirty = true
+		p.flushLock.Unlock()
+	}
+	return err
+}
+
+// markDirty schedules a write after a change, changes made before it runs are written with it
+func (p *PersistentData) markDirty() {
+	p.flushLock.Lock()
+	defer p.flushLock.Unlock()
+	p.dirty = true
+	if p.flushTimer == nil {
+		p.flushTimer = time.AfterFunc(PersistentSaveDelay, p.autoSave)
+	}
+}
+
+// autoSave writes the changes once PersistentSaveDelay has passed, errors are logged as the setters that cause it do not return them
+func (p *PersistentData) autoSave() {
+	err := p.Flush()
+	if err != nil {
+		log.Println("Error saving persistent data: ", err)
+	}
+}
+
+// LineKey returns the key a line of dialogue is tracked under in ReadLines
+func LineKey(scene, speaker, text string) string {
+	sum := sha256.Sum256([]byte(speaker + "\n" + text))
+	return scene + ":" + hex.EncodeToString(sum[:8])
+}
+
+// MarkRead remembers that the player has seen the line, lines that were already read are not saved again
+func (p *PersistentData) MarkRead(scene, speaker, text string) {
+	key := LineKey(scene, speaker, text)
+	p.lock.Lock()
+	if p.ReadLines[key] {
+		p.lock.Unlock()
+		return
+	}
+	p.ReadLines[key] = true
+	p.lock.Unlock()
+	p.changed()
+}
+
+// IsRead returns true if the player has seen the line in any save, this is what "skip read text only" checks
+func (p *PersistentData) IsRead(scene, speaker, text string) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.ReadLines[LineKey(scene, speaker, text)]
+}
+
+// ClearRead forgets every line the player has seen
+func (p *PersistentData) ClearRead() {
+	p.lock.Lock()
+	p.ReadLines = map[string]bool{}
+	p.lock.Unlock()
+	p.changed()
+}
+
+// Unlock unlocks the key, such as a gallery image, for every save
+func (p *PersistentData) Unlock(key string) {
+	p.SetBool(UnlockPrefix+key, true)
+}
+
+// IsUnlocked returns true if the key was unlocked in any save
+func (p *PersistentData) IsUnlocked(key string) bool {
+	unlocked, err := p.GetBool(UnlockPrefix + key)
+	return err == nil && unlocked
+}
+
+// GetUnlocks returns every key that was unlocked
+func (p *PersistentData) GetUnlocks() []string {
+	return p.withPrefix(UnlockPrefix)
+}
+
+// RecordEnding remembers that the player reached the ending
+func (p *PersistentData) RecordEnding(ending string) {
+	p.SetBool(EndingPrefix+ending, true)
+}
+
+// ReachedEnding returns true if the player reached the ending in any save
+func (p *PersistentData) ReachedEnding(ending string) bool {
+	reached, err := p.GetBool(EndingPrefix + ending)
+	return err == nil && reached
+}
+
+// GetEndings returns every ending the player reached
+func (p *PersistentData) GetEndings() []string {
+	return p.withPrefix(EndingPrefix)
+}
+
+// withPrefix returns the keys that are set to true in the bool data and start with the prefix, without the prefix
+func (p *PersistentData) withPrefix(prefix string) []string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	keys := make([]string, 0)
+	for key, set := range p.BoolData {
+		if set && strings.HasPrefix(key, prefix) {
+			keys = append(keys, strings.TrimPrefix(key, prefix))
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
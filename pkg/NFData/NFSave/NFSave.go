@@ -3,9 +3,17 @@ package NFSave
 import (
 	"encoding/json"
 	"errors"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFConfig"
 	"go.novellaforge.dev/novellaforge/pkg/NFData/NFEncryption"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"io/fs"
+	"log"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -13,21 +21,45 @@ import (
 
 const Extension = ".novella" // This is the extension that will be used for save files make sure it has the dot at the beginning
 var Active *Save
+
+// Directory is the folder saves are kept in by the default Store
 var Directory = ""
+
 var ErrSaveNameNotSet = errors.New("save name not yet set")
+
+// SaveEncryption is whether new saves and persistent data are encrypted, what is read is decrypted only if it is encrypted
+// so saves written before it was changed keep loading
 var SaveEncryption = false
 var SaveHistory = true
-var SaveEncryptionKey = "NovellaForge"
+
+// SaveEncryptionKey is the password saves are encrypted with when SaveEncryption is on,
+// if it is empty the EncryptionKey of NFConfig.Game is used instead
+var SaveEncryptionKey = ""
+
+// defaultEncryptionKey is the password used when neither SaveEncryptionKey nor the config set one,
+// it was the only password before they could be set, so saves that fail to decrypt with the set password are tried with it
+const defaultEncryptionKey = "NovellaForge"
 
 // Save is the struct that will be used to save data
 type Save struct {
-	Name       string             `json:"Name"`
-	Scene      string             `json:"Scene"`
-	Time       time.Time          `json:"Saved At"`
-	IntData    map[string]int     `json:"IntData,omitempty"`
-	FloatData  map[string]float64 `json:"FloatData,omitempty"`
-	StringData map[string]string  `json:"StringData,omitempty"`
-	BoolData   map[string]bool    `json:"BoolData,omitempty"`
+	SchemaVersion int                         `json:"SchemaVersion"`
+	Name          string                      `json:"Name"`
+	Scene         string                      `json:"Scene"`
+	SceneStack    []string                    `json:"SceneStack,omitempty"`
+	SceneState    map[string]int              `json:"SceneState,omitempty"`
+	SceneStates   []map[string]int            `json:"SceneStates,omitempty"`
+	Time          time.Time                   `json:"Saved At"`
+	Backlog       []BacklogEntry              `json:"Backlog,omitempty"`
+	Stages        map[string][]StageCharacter `json:"Stages,omitempty"`
+	Chapter       string                      `json:"Chapter,omitempty"`
+	Playtime      time.Duration               `json:"Playtime"`
+	//Sections holds the state of the registered StateProviders by name, see CaptureState
+	Sections map[string]json.RawMessage `json:"Sections,omitempty"`
+	//Data holds the variables of the playthrough, its maps are encoded as fields of the save
+	Data
+
+	//sessionStart is when the playtime was last added to, so the time played since can be counted
+	sessionStart time.Time
 }
 
 // GetActive and SetActive are used to get and set the active save
@@ -61,8 +93,16 @@ func SetSaveHistory(value bool) {
 }
 
 // GetSaveEncryptionKey and SetSaveEncryptionKey are used to get and set the save encryption key
+//
+// GetSaveEncryptionKey returns the password saves are actually encrypted with, falling back to the config's key
 func GetSaveEncryptionKey() string {
-	return SaveEncryptionKey
+	if SaveEncryptionKey != "" {
+		return SaveEncryptionKey
+	}
+	if NFConfig.Game.EncryptionKey != "" {
+		return NFConfig.Game.EncryptionKey
+	}
+	return defaultEncryptionKey
 }
 
 // SetSaveEncryptionKey is used to set the save encryption key
@@ -74,22 +114,24 @@ func SetSaveEncryptionKey(value string) {
 func New(scene string) (*Save, error) {
 	//Create the save struct
 	save := Save{
-		Name:       "",
-		Scene:      scene,
-		Time:       time.Now(),
-		IntData:    map[string]int{},
-		FloatData:  map[string]float64{},
-		StringData: map[string]string{},
-		BoolData:   map[string]bool{},
+		SchemaVersion: CurrentSchemaVersion,
+		Name:          "",
+		Scene:         scene,
+		Time:          time.Now(),
+		Data:          NewData(),
+		sessionStart:  time.Now(),
 	}
 
 	return &save, nil
 }
 
-// Load loads a save file
+// Load loads a save file from a path on disk, running the registered migrations on saves from older schema versions
+//
+// Saves from a newer schema version than CurrentSchemaVersion are refused with NFError.ErrSaveTooNew.
+// Saves in the Store are loaded by name with LoadNamed
 func Load(savePath string) (*Save, error) {
 	//Check if it is a valid save file
-	if filepath.Ext(savePath) != Extension && filepath.Ext(savePath) != Extension+"history" {
+	if filepath.Ext(savePath) != Extension && filepath.Ext(savePath) != HistoryExtension {
 		return nil, errors.New("invalid save file")
 	}
 	//Try to load the file at the path
@@ -97,147 +139,198 @@ func Load(savePath string) (*Save, error) {
 	if err != nil {
 		return nil, err
 	}
-	//Decrypt the fileBytes if needed
-	if SaveEncryption {
-		fileBytes, err = NFEncryption.Decrypt(fileBytes, SaveEncryptionKey)
+	return decode(fileBytes)
+}
+
+// SaveInfo is a named save in the Store
+type SaveInfo struct {
+	Name string
+	// Time is when the save was last written, it is zero if the Store can not tell
+	Time time.Time
+}
+
+// ListSaves returns the named saves in the Store with the most recently written first
+func ListSaves() ([]SaveInfo, error) {
+	entries, err := Store.List("")
+	if errors.Is(err, fs.ErrNotExist) {
+		return []SaveInfo{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	saves := make([]SaveInfo, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir || entry.Name == SlotsFolder {
+			continue
+		}
+		file, err := Store.Stat(savePath(entry.Name))
+		if err == nil {
+			saves = append(saves, SaveInfo{Name: entry.Name, Time: file.ModTime})
+			continue
+		}
+		//A save whose file is gone after a crash while it was written is still listed if it has snapshots to recover from
+		if snapshots, err := ListSnapshots(entry.Name); err == nil && len(snapshots) > 0 {
+			saves = append(saves, SaveInfo{Name: entry.Name, Time: snapshots[len(snapshots)-1].Time})
+		}
+	}
+	slices.SortFunc(saves, func(a, b SaveInfo) int {
+		return b.Time.Compare(a.Time)
+	})
+	return saves, nil
+}
+
+// SaveExists returns true if there is a save with the name in the Store
+func SaveExists(name string) bool {
+	ok, _ := exists(savePath(name))
+	if !ok {
+		ok, _ = exists(path.Join(name, HistoryFolder))
+	}
+	return ok
+}
+
+// LoadNamed loads the save with the name from the Store, it does not make it the active save
+//
+// If the save is missing or corrupt, such as after a crash while it was written, the newest snapshot of it that loads is
+// returned instead along with an error matching NFError.ErrSaveRecovered that says which snapshot was used.
+// Callers can use the save in that case and should let the player know
+func LoadNamed(name string) (*Save, error) {
+	fileBytes, err := Store.Read(savePath(name))
+	if err == nil {
+		var save *Save
+		save, err = decode(fileBytes)
+		if err == nil {
+			save.Name = name
+			return save, nil
+		}
+	}
+	if !recoverable(err) {
+		return nil, err
+	}
+	save, recoverErr := recoverSave(name, err)
+	if save == nil && errors.Is(err, fs.ErrNotExist) {
+		return nil, NFError.NewErrNotFound("no save named " + name)
+	}
+	if save != nil {
+		save.Name = name
+	}
+	return save, recoverErr
+}
+
+// decode decrypts the bytes of a save file if needed, migrates them and decodes them into a save struct
+func decode(fileBytes []byte) (*Save, error) {
+	//Check the checksum before anything else reads the file
+	fileBytes, err := unseal(fileBytes)
+	if err != nil {
+		return nil, err
+	}
+	//Decrypt the fileBytes if they are encrypted, whether or not SaveEncryption is set now
+	if isEncrypted(fileBytes) {
+		fileBytes, err = decrypt(fileBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	//Binary saves are turned in to JSON so they are migrated and decoded like any other save
+	if isBinary(fileBytes) {
+		fileBytes, err = binaryToJSON(fileBytes)
 		if err != nil {
 			return nil, err
 		}
 	}
+	//Bring saves from older versions of the game up to date
+	fileBytes, err = migrate(fileBytes)
+	if err != nil {
+		return nil, corrupt(err)
+	}
 	//Decode the fileBytes into a save struct
 	save := Save{}
 	err = json.Unmarshal(fileBytes, &save)
 	if err != nil {
-		return nil, err
+		return nil, corrupt(err)
 	}
+	save.initData()
+	save.sessionStart = time.Now()
 
 	return &save, nil
 }
 
-func (s *Save) Save() error {
-	err := os.MkdirAll(Directory, os.ModePerm)
-	if err != nil {
-		return err
-	}
-
-	//Check if the save file has a name
-	if s.Name == "" {
-		return ErrSaveNameNotSet
-	}
-
-	saveIsNew := false
-	//Check if the save already exists
-	var SaveFile *os.File
-	_, err = os.Stat(Directory + s.Name + "/save" + Extension)
-	if err != nil {
-		//If the save does not exist, create a new save folder
-		err = os.MkdirAll(Directory+s.Name, os.ModePerm)
-		if err != nil {
-			return err
-		}
-		saveIsNew = true
-		//Create a new save file
-		SaveFile, err = os.Create(Directory + s.Name + "/save" + Extension)
-		if err != nil {
-			return err
-		}
-	} else {
-		SaveFile, err = os.Open(Directory + s.Name + "/save" + Extension)
-		if err != nil {
-			return err
+// decrypt decrypts the bytes of a save file with the save password, trying the default password if that fails
+// so saves from before the password was set keep loading
+func decrypt(fileBytes []byte) ([]byte, error) {
+	key := GetSaveEncryptionKey()
+	plaintext, err := NFEncryption.Decrypt(fileBytes, key)
+	if err != nil && key != defaultEncryptionKey {
+		if legacyPlaintext, legacyErr := NFEncryption.Decrypt(fileBytes, defaultEncryptionKey); legacyErr == nil {
+			return legacyPlaintext, nil
 		}
 	}
-	defer SaveFile.Close()
+	return plaintext, err
+}
 
-	if SaveHistory {
-		//Create a folder with the save name with a history folder inside
-		err = os.MkdirAll(Directory+s.Name+"/history", os.ModePerm)
+// encode stamps the save time, adds the time played since the last write to the playtime and encodes the save
+// with GetSaveEncoding, encrypting it if needed and adding a checksum, see IntegrityMagic. The active save captures the state of the registered StateProviders first
+func (s *Save) encode() ([]byte, error) {
+	if s == Active {
+		//A provider that fails should not stop the rest of the save from being written
+		err := s.CaptureState()
 		if err != nil {
-			return err
-		}
-		if !saveIsNew {
-			//If the save file exists, count the number of history files in the history folder
-			count := 0
-			//Walk the history folder
-			err = filepath.WalkDir(Directory+s.Name+"/history", func(path string, info os.DirEntry, err error) error {
-				//If the file is a json file, increment the count
-				if filepath.Ext(path) == Extension+"history" {
-					count++
-				}
-				return nil
-			})
-			if err != nil {
-				return err
-			}
-			//Rename the save file to a history file
-			newName := Directory + s.Name + "/history/save" + string(rune(count+1)) + Extension + "history"
-
-			//get the current save files data and move it to a history file before saving the new save file
-			oldSave := Save{}
-			//Decode the save file into the oldSave struct
-			err = json.NewDecoder(SaveFile).Decode(&oldSave)
-			if err != nil {
-				return err
-			}
-
-			var fileBytes []byte
-			if SaveEncryption {
-				//Convert oldSave into a byte array
-				var oldSaveBytes []byte
-				oldSaveBytes, err = json.Marshal(oldSave)
-				if err != nil {
-					return err
-				}
-				//Encrypt the byte array
-				fileBytes, err = NFEncryption.Encrypt(oldSaveBytes, SaveEncryptionKey)
-				if err != nil {
-					return err
-				}
-			} else {
-				//Just marshal the bytes with indentation
-				fileBytes, err = json.MarshalIndent(oldSave, "", "    ")
-				if err != nil {
-					return err
-				}
-			}
-
-			newHistoryFile, err := os.Create(newName)
-			if err != nil {
-				return err
-			}
-
-			//Write the file bytes to the new history file
-			_, err = newHistoryFile.Write(fileBytes)
-			if err != nil {
-				return err
-			}
+			log.Println("Error capturing save state: ", err)
 		}
 	}
-	var saveBytes []byte
+	s.Time = time.Now()
+	s.Playtime = s.GetPlaytime()
+	s.sessionStart = s.Time
+	//Convert the save struct into a byte array, JSON is only indented when it can be read
+	saveBytes, err := s.marshal(GetSaveEncoding(), !SaveEncryption)
+	if err != nil {
+		return nil, err
+	}
 	if SaveEncryption {
-		//Convert the save struct into a byte array
-		saveBytes, err = json.Marshal(s)
-		if err != nil {
-			return err
-		}
 		//Encrypt the byte array
-		saveBytes, err = NFEncryption.Encrypt(saveBytes, SaveEncryptionKey)
+		saveBytes, err = NFEncryption.Encrypt(saveBytes, GetSaveEncryptionKey())
 		if err != nil {
-			return err
+			return nil, err
 		}
-	} else {
-		//Just marshal the bytes with indentation
-		saveBytes, err = json.MarshalIndent(s, "", "    ")
+	}
+	return seal(saveBytes), nil
+}
+
+// GetPlaytime returns how long the save has been played, including the time since it was created or loaded
+func (s *Save) GetPlaytime() time.Duration {
+	if s.sessionStart.IsZero() {
+		return s.Playtime
+	}
+	return s.Playtime + time.Since(s.sessionStart)
+}
+
+// SetChapter is used to set the chapter label shown in save slots
+func (s *Save) SetChapter(chapter string) {
+	s.Chapter = chapter
+}
+
+// GetChapter is used to get the chapter label shown in save slots
+func (s *Save) GetChapter() string {
+	return s.Chapter
+}
+
+// Save writes the save to its folder in the Store, the save must have a name
+//
+// If SaveHistory is on the file being overwritten is kept as a snapshot, see ListSnapshots and RestoreSnapshot
+func (s *Save) Save() error {
+	//Check if the save file has a name
+	if s.Name == "" {
+		return ErrSaveNameNotSet
+	}
+	if SaveHistory {
+		err := snapshot(s.Name, HistoryLimit)
 		if err != nil {
 			return err
 		}
 	}
-	//Write the save bytes to the save file
-	_, err = SaveFile.Write(saveBytes)
+	saveBytes, err := s.encode()
 	if err != nil {
 		return err
 	}
-	return nil
+	return Store.Write(savePath(s.Name), saveBytes)
 }
 
 // SetSaveName is used to set the save name
@@ -260,230 +353,133 @@ func (s *Save) GetScene() string {
 	return s.Scene
 }
 
-// SetInt is used to set an int value in the save file
-func (s *Save) SetInt(key string, value int) {
-	s.IntData[key] = value
-}
-
-// SetFloat is used to set a float value in the save file
-func (s *Save) SetFloat(key string, value float64) {
-	s.FloatData[key] = value
-}
-
-// SetString is used to set a string value in the save file
-func (s *Save) SetString(key string, value string) {
-	s.StringData[key] = value
-}
-
-// SetBool is used to set a bool value in the save file
-func (s *Save) SetBool(key string, value bool) {
-	s.BoolData[key] = value
-}
-
-// GetInt is used to get an int value from the save file
-func (s *Save) GetInt(key string) (int, error) {
-	if value, ok := s.IntData[key]; ok {
-		return value, nil
-	}
-	return 0, errors.New("key not found")
-}
-
-// GetFloat is used to get a float value from the save file
-func (s *Save) GetFloat(key string) (float64, error) {
-	if value, ok := s.FloatData[key]; ok {
-		return value, nil
-	}
-	return 0, errors.New("key not found")
+// SetSceneStack is used to set the scenes that can be returned to with PopScene
+func (s *Save) SetSceneStack(stack []string) {
+	s.SceneStack = stack
 }
 
-// GetString is used to get a string value from the save file
-func (s *Save) GetString(key string) (string, error) {
-	if value, ok := s.StringData[key]; ok {
-		return value, nil
-	}
-	return "", errors.New("key not found")
+// GetSceneStack is used to get the scenes that can be returned to with PopScene
+func (s *Save) GetSceneStack() []string {
+	return s.SceneStack
 }
 
-// GetBool is used to get a bool value from the save file
-func (s *Save) GetBool(key string) (bool, error) {
-	if value, ok := s.BoolData[key]; ok {
-		return value, nil
+// SetSceneState is used to remember the state of a widget in the current scene, such as the line a NarrativeBox is on,
+// so that it can be restored when the save is resumed
+func (s *Save) SetSceneState(key string, value int) {
+	if s.SceneState == nil {
+		s.SceneState = map[string]int{}
 	}
-	return false, errors.New("key not found")
+	s.SceneState[key] = value
 }
 
-// DeleteInt is used to delete an int value from the save file
-func (s *Save) DeleteInt(key string) {
-	delete(s.IntData, key)
+// GetSceneState is used to get the state of a widget in the current scene
+func (s *Save) GetSceneState(key string) (int, bool) {
+	value, ok := s.SceneState[key]
+	return value, ok
 }
 
-// DeleteFloat is used to delete a float value from the save file
-func (s *Save) DeleteFloat(key string) {
-	delete(s.FloatData, key)
+// ClearSceneState is used to forget the state of the widgets in the current scene, this happens when a new scene is entered
+func (s *Save) ClearSceneState() {
+	s.SceneState = nil
 }
 
-// DeleteString is used to delete a string value from the save file
-func (s *Save) DeleteString(key string) {
-	delete(s.StringData, key)
+// PushSceneState remembers the scene state of a scene that is pushed on to the SceneStack, it is returned by PopSceneState
+func (s *Save) PushSceneState(state map[string]int) {
+	s.SceneStates = append(s.SceneStates, maps.Clone(state))
 }
 
-// DeleteBool is used to delete a bool value from the save file
-func (s *Save) DeleteBool(key string) {
-	delete(s.BoolData, key)
-}
-
-// SafeDeleteInt is used to delete an int value from the save file (This method will return an error if the key does not exist unlike DeleteInt)
-func (s *Save) SafeDeleteInt(key string) error {
-	if _, ok := s.IntData[key]; ok {
-		delete(s.IntData, key)
+// PopSceneState returns the scene state of the scene last pushed on to the SceneStack and forgets it,
+// saves from before the states were kept return nil so the scene starts fresh
+func (s *Save) PopSceneState() map[string]int {
+	if len(s.SceneStates) == 0 {
 		return nil
 	}
-	return errors.New("key not found")
+	state := s.SceneStates[len(s.SceneStates)-1]
+	s.SceneStates = s.SceneStates[:len(s.SceneStates)-1]
+	return state
 }
 
-// SafeDeleteFloat is used to delete a float value from the save file (This method will return an error if the key does not exist unlike DeleteFloat)
-func (s *Save) SafeDeleteFloat(key string) error {
-	if _, ok := s.FloatData[key]; ok {
-		delete(s.FloatData, key)
-		return nil
-	}
-	return errors.New("key not found")
+// ClearSceneStates forgets the scene state of every scene on the SceneStack, this happens when the stack is cleared
+func (s *Save) ClearSceneStates() {
+	s.SceneStates = nil
 }
 
-// SafeDeleteString is used to delete a string value from the save file (This method will return an error if the key does not exist unlike DeleteString)
-func (s *Save) SafeDeleteString(key string) error {
-	if _, ok := s.StringData[key]; ok {
-		delete(s.StringData, key)
+// cloneSceneStates deep copies the scene states of the scenes on the SceneStack
+func cloneSceneStates(states []map[string]int) []map[string]int {
+	if states == nil {
 		return nil
 	}
-	return errors.New("key not found")
-}
-
-// SafeDeleteBool is used to delete a bool value from the save file (This method will return an error if the key does not exist unlike DeleteBool)
-func (s *Save) SafeDeleteBool(key string) error {
-	if _, ok := s.BoolData[key]; ok {
-		delete(s.BoolData, key)
-		return nil
+	clone := make([]map[string]int, len(states))
+	for i, state := range states {
+		clone[i] = maps.Clone(state)
 	}
-	return errors.New("key not found")
+	return clone
 }
 
-// DeleteAll is used to delete all values from the save file
-func (s *Save) DeleteAll() {
-	s.IntData = map[string]int{}
-	s.FloatData = map[string]float64{}
-	s.StringData = map[string]string{}
-	s.BoolData = map[string]bool{}
-}
+// ChoicePrefix is the prefix of the bool data keys used to remember which choice menu options were picked
+const ChoicePrefix = "Chosen."
 
-// DeleteAllInt is used to delete all int values from the save file
-func (s *Save) DeleteAllInt() {
-	s.IntData = map[string]int{}
+// ChoiceKey returns the bool data key used to remember that the option of the choice menu was picked
+func ChoiceKey(menu, option string) string {
+	return ChoicePrefix + menu + "." + option
 }
 
-// DeleteAllFloat is used to delete all float values from the save file
-func (s *Save) DeleteAllFloat() {
-	s.FloatData = map[string]float64{}
+// RecordChoice remembers that the option of the choice menu was picked
+func (s *Save) RecordChoice(menu, option string) {
+	s.SetBool(ChoiceKey(menu, option), true)
 }
 
-// DeleteAllString is used to delete all string values from the save file
-func (s *Save) DeleteAllString() {
-	s.StringData = map[string]string{}
+// WasChosen returns true if the option of the choice menu was ever picked in this save
+func (s *Save) WasChosen(menu, option string) bool {
+	chosen, err := s.GetBool(ChoiceKey(menu, option))
+	return err == nil && chosen
 }
 
-// DeleteAllBool is used to delete all bool values from the save file
-func (s *Save) DeleteAllBool() {
-	s.BoolData = map[string]bool{}
-}
-
-// UpdateInt is used to update an int value in the save file (This method will return an error if the key does not exist unlike SetInt)
-func (s *Save) UpdateInt(key string, value int) error {
-	if _, ok := s.IntData[key]; ok {
-		s.IntData[key] = value
-		return nil
+// GetChoices returns all the options of the choice menu that were picked in this save
+func (s *Save) GetChoices(menu string) []string {
+	prefix := ChoiceKey(menu, "")
+	options := make([]string, 0)
+	for key, chosen := range s.BoolData {
+		if chosen && strings.HasPrefix(key, prefix) {
+			options = append(options, strings.TrimPrefix(key, prefix))
+		}
 	}
-	return errors.New("key not found")
+	slices.Sort(options)
+	return options
 }
 
-// UpdateFloat is used to update a float value in the save file (This method will return an error if the key does not exist unlike SetFloat)
-func (s *Save) UpdateFloat(key string, value float64) error {
-	if _, ok := s.FloatData[key]; ok {
-		s.FloatData[key] = value
-		return nil
-	}
-	return errors.New("key not found")
+// StageCharacter is a character shown on a stage, it is kept in the save so loading restores who is on screen
+type StageCharacter struct {
+	Character  string `json:"Character"`
+	Expression string `json:"Expression,omitempty"`
+	Slot       string `json:"Slot"`
 }
 
-// UpdateString is used to update a string value in the save file (This method will return an error if the key does not exist unlike SetString)
-func (s *Save) UpdateString(key string, value string) error {
-	if _, ok := s.StringData[key]; ok {
-		s.StringData[key] = value
-		return nil
+// SetStage is used to remember the characters on the named stage in the order they are drawn
+func (s *Save) SetStage(stage string, characters []StageCharacter) {
+	if s.Stages == nil {
+		s.Stages = map[string][]StageCharacter{}
 	}
-	return errors.New("key not found")
-}
-
-// UpdateBool is used to update a bool value in the save file (This method will return an error if the key does not exist unlike SetBool)
-func (s *Save) UpdateBool(key string, value bool) error {
-	if _, ok := s.BoolData[key]; ok {
-		s.BoolData[key] = value
-		return nil
-	}
-	return errors.New("key not found")
-}
-
-// GetInts is used to get all int values from the save file
-func (s *Save) GetInts() map[string]int {
-	return s.IntData
-}
-
-// GetFloats is used to get all float values from the save file
-func (s *Save) GetFloats() map[string]float64 {
-	return s.FloatData
-}
-
-// GetStrings is used to get all string values from the save file
-func (s *Save) GetStrings() map[string]string {
-	return s.StringData
-}
-
-// GetBools is used to get all bool values from the save file
-func (s *Save) GetBools() map[string]bool {
-	return s.BoolData
-}
-
-// GetIntKeys is used to get all int keys from the save file
-func (s *Save) GetIntKeys() []string {
-	var keys []string
-	for key := range s.IntData {
-		keys = append(keys, key)
+	if len(characters) == 0 {
+		delete(s.Stages, stage)
+		return
 	}
-	return keys
+	s.Stages[stage] = slices.Clone(characters)
 }
 
-// GetFloatKeys is used to get all float keys from the save file
-func (s *Save) GetFloatKeys() []string {
-	var keys []string
-	for key := range s.FloatData {
-		keys = append(keys, key)
-	}
-	return keys
+// GetStage is used to get the characters on the named stage in the order they are drawn
+func (s *Save) GetStage(stage string) []StageCharacter {
+	return slices.Clone(s.Stages[stage])
 }
 
-// GetStringKeys is used to get all string keys from the save file
-func (s *Save) GetStringKeys() []string {
-	var keys []string
-	for key := range s.StringData {
-		keys = append(keys, key)
+// cloneStages copies the stages so that a snapshot is not changed by later changes to the save
+func cloneStages(stages map[string][]StageCharacter) map[string][]StageCharacter {
+	if stages == nil {
+		return nil
 	}
-	return keys
-}
-
-// GetBoolKeys is used to get all bool keys from the save file
-func (s *Save) GetBoolKeys() []string {
-	var keys []string
-	for key := range s.BoolData {
-		keys = append(keys, key)
+	clone := make(map[string][]StageCharacter, len(stages))
+	for stage, characters := range stages {
+		clone[stage] = slices.Clone(characters)
 	}
-	return keys
+	return clone
 }
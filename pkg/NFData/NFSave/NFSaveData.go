@@ -0,0 +1,416 @@
+package NFSave
+
+import (
+	"errors"
+	"sync"
+)
+
+// Data holds values by key in maps for each type, it is embedded in Save for the variables of a playthrough
+// and in PersistentData for the variables shared by every playthrough
+type Data struct {
+	IntData    map[string]int     `json:"IntData,omitempty"`
+	FloatData  map[string]float64 `json:"FloatData,omitempty"`
+	StringData map[string]string  `json:"StringData,omitempty"`
+	BoolData   map[string]bool    `json:"BoolData,omitempty"`
+
+	//onChange is called after any value is changed, PersistentData uses it to save itself
+	onChange func()
+	//lock guards the maps when it is set, PersistentData sets it as it is written from other goroutines
+	lock *sync.RWMutex
+}
+
+// NewData creates empty typed data
+func NewData() Data {
+	return Data{
+		IntData:    map[string]int{},
+		FloatData:  map[string]float64{},
+		StringData: map[string]string{},
+		BoolData:   map[string]bool{},
+	}
+}
+
+// changed runs onChange if it is set
+func (d *Data) changed() {
+	if d.onChange != nil {
+		d.onChange()
+	}
+}
+
+// lockWrite locks the maps for a change if the data has a lock, the returned function unlocks them
+func (d *Data) lockWrite() func() {
+	if d.lock == nil {
+		return func() {}
+	}
+	d.lock.Lock()
+	return d.lock.Unlock
+}
+
+// lockRead locks the maps for reading if the data has a lock, the returned function unlocks them
+func (d *Data) lockRead() func() {
+	if d.lock == nil {
+		return func() {}
+	}
+	d.lock.RLock()
+	return d.lock.RUnlock
+}
+
+// initData makes sure none of the typed data maps are nil, as they are omitted from the save file when empty
+func (d *Data) initData() {
+	if d.IntData == nil {
+		d.IntData = map[string]int{}
+	}
+	if d.FloatData == nil {
+		d.FloatData = map[string]float64{}
+	}
+	if d.StringData == nil {
+		d.StringData = map[string]string{}
+	}
+	if d.BoolData == nil {
+		d.BoolData = map[string]bool{}
+	}
+}
+
+// SetInt is used to set an int value in the save file
+func (d *Data) SetInt(key string, value int) {
+	defer d.lockWrite()()
+	d.IntData[key] = value
+	d.changed()
+}
+
+// SetFloat is used to set a float value in the save file
+func (d *Data) SetFloat(key string, value float64) {
+	defer d.lockWrite()()
+	d.FloatData[key] = value
+	d.changed()
+}
+
+// SetString is used to set a string value in the save file
+func (d *Data) SetString(key string, value string) {
+	defer d.lockWrite()()
+	d.StringData[key] = value
+	d.changed()
+}
+
+// SetBool is used to set a bool value in the save file
+func (d *Data) SetBool(key string, value bool) {
+	defer d.lockWrite()()
+	d.BoolData[key] = value
+	d.changed()
+}
+
+// GetInt is used to get an int value from the save file
+func (d *Data) GetInt(key string) (int, error) {
+	defer d.lockRead()()
+	if value, ok := d.IntData[key]; ok {
+		return value, nil
+	}
+	return 0, errors.New("key not found")
+}
+
+// GetFloat is used to get a float value from the save file
+func (d *Data) GetFloat(key string) (float64, error) {
+	defer d.lockRead()()
+	if value, ok := d.FloatData[key]; ok {
+		return value, nil
+	}
+	return 0, errors.New("key not found")
+}
+
+// GetString is used to get a string value from the save file
+func (d *Data) GetString(key string) (string, error) {
+	defer d.lockRead()()
+	if value, ok := d.StringData[key]; ok {
+		return value, nil
+	}
+	return "", errors.New("key not found")
+}
+
+// GetBool is used to get a bool value from the save file
+func (d *Data) GetBool(key string) (bool, error) {
+	defer d.lockRead()()
+	if value, ok := d.BoolData[key]; ok {
+		return value, nil
+	}
+	return false, errors.New("key not found")
+}
+
+// DeleteInt is used to delete an int value from the save file
+func (d *Data) DeleteInt(key string) {
+	defer d.lockWrite()()
+	delete(d.IntData, key)
+	d.changed()
+}
+
+// DeleteFloat is used to delete a float value from the save file
+func (d *Data) DeleteFloat(key string) {
+	defer d.lockWrite()()
+	delete(d.FloatData, key)
+	d.changed()
+}
+
+// DeleteString is used to delete a string value from the save file
+func (d *Data) DeleteString(key string) {
+	defer d.lockWrite()()
+	delete(d.StringData, key)
+	d.changed()
+}
+
+// DeleteBool is used to delete a bool value from the save file
+func (d *Data) DeleteBool(key string) {
+	defer d.lockWrite()()
+	delete(d.BoolData, key)
+	d.changed()
+}
+
+// SafeDeleteInt is used to delete an int value from the save file (This method will return an error if the key does not exist unlike DeleteInt)
+func (d *Data) SafeDeleteInt(key string) error {
+	defer d.lockWrite()()
+	if _, ok := d.IntData[key]; ok {
+		delete(d.IntData, key)
+		d.changed()
+		return nil
+	}
+	return errors.New("key not found")
+}
+
+// SafeDeleteFloat is used to delete a float value from the save file (This method will return an error if the key does not exist unlike DeleteFloat)
+func (d *Data) SafeDeleteFloat(key string) error {
+	defer d.lockWrite()()
+	if _, ok := d.FloatData[key]; ok {
+		delete(d.FloatData, key)
+		d.changed()
+		return nil
+	}
+	return errors.New("key not found")
+}
+
+// SafeDeleteString is used to delete a string value from the save file (This method will return an error if the key does not exist unlike DeleteString)
+func (d *Data) SafeDeleteString(key string) error {
+	defer d.lockWrite()()
+	if _, ok := d.StringData[key]; ok {
+		delete(d.StringData, key)
+		d.changed()
+		return nil
+	}
+	return errors.New("key not found")
+}
+
+// SafeDeleteBool is used to delete a bool value from the save file (This method will return an error if the key does not exist unlike DeleteBool)
+func (d *Data) SafeDeleteBool(key string) error {
+	defer d.lockWrite()()
+	if _, ok := d.BoolData[key]; ok {
+		delete(d.BoolData, key)
+		d.changed()
+		return nil
+	}
+	return errors.New("key not found")
+}
+
+// GetValue is used to get a value of any type from the save file, checking the int, float, string and bool data in that order
+func (d *Data) GetValue(key string) (interface{}, bool) {
+	defer d.lockRead()()
+	return d.getValue(key)
+}
+
+// getValue is GetValue for callers that already hold the lock
+func (d *Data) getValue(key string) (interface{}, bool) {
+	if value, ok := d.IntData[key]; ok {
+		return value, true
+	}
+	if value, ok := d.FloatData[key]; ok {
+		return value, true
+	}
+	if value, ok := d.StringData[key]; ok {
+		return value, true
+	}
+	if value, ok := d.BoolData[key]; ok {
+		return value, true
+	}
+	return nil, false
+}
+
+// SetValue is used to set a value in the typed data matching the type of the value,
+// any value stored under the same key with a different type is removed so that the key is never ambiguous
+func (d *Data) SetValue(key string, value interface{}) error {
+	defer d.lockWrite()()
+	switch v := value.(type) {
+	case int:
+		d.deleteValue(key)
+		d.IntData[key] = v
+	case float64:
+		d.deleteValue(key)
+		d.FloatData[key] = v
+	case string:
+		d.deleteValue(key)
+		d.StringData[key] = v
+	case bool:
+		d.deleteValue(key)
+		d.BoolData[key] = v
+	default:
+		return errors.New("unsupported save value type")
+	}
+	d.changed()
+	return nil
+}
+
+// DeleteValue is used to delete a value of any type from the save file, it returns false if the key was not found
+func (d *Data) DeleteValue(key string) bool {
+	defer d.lockWrite()()
+	found := d.deleteValue(key)
+	if found {
+		d.changed()
+	}
+	return found
+}
+
+// deleteValue removes the key from all the typed data without calling onChange
+func (d *Data) deleteValue(key string) bool {
+	_, found := d.getValue(key)
+	delete(d.IntData, key)
+	delete(d.FloatData, key)
+	delete(d.StringData, key)
+	delete(d.BoolData, key)
+	return found
+}
+
+// DeleteAll is used to delete all values from the save file
+func (d *Data) DeleteAll() {
+	defer d.lockWrite()()
+	d.IntData = map[string]int{}
+	d.FloatData = map[string]float64{}
+	d.StringData = map[string]string{}
+	d.BoolData = map[string]bool{}
+	d.changed()
+}
+
+// DeleteAllInt is used to delete all int values from the save file
+func (d *Data) DeleteAllInt() {
+	defer d.lockWrite()()
+	d.IntData = map[string]int{}
+	d.changed()
+}
+
+// DeleteAllFloat is used to delete all float values from the save file
+func (d *Data) DeleteAllFloat() {
+	defer d.lockWrite()()
+	d.FloatData = map[string]float64{}
+	d.changed()
+}
+
+// DeleteAllString is used to delete all string values from the save file
+func (d *Data) DeleteAllString() {
+	defer d.lockWrite()()
+	d.StringData = map[string]string{}
+	d.changed()
+}
+
+// DeleteAllBool is used to delete all bool values from the save file
+func (d *Data) DeleteAllBool() {
+	defer d.lockWrite()()
+	d.BoolData = map[string]bool{}
+	d.changed()
+}
+
+// UpdateInt is used to update an int value in the save file (This method will return an error if the key does not exist unlike SetInt)
+func (d *Data) UpdateInt(key string, value int) error {
+	defer d.lockWrite()()
+	if _, ok := d.IntData[key]; ok {
+		d.IntData[key] = value
+		d.changed()
+		return nil
+	}
+	return errors.New("key not found")
+}
+
+// UpdateFloat is used to update a float value in the save file (This method will return an error if the key does not exist unlike SetFloat)
+func (d *Data) UpdateFloat(key string, value float64) error {
+	defer d.lockWrite()()
+	if _, ok := d.FloatData[key]; ok {
+		d.FloatData[key] = value
+		d.changed()
+		return nil
+	}
+	return errors.New("key not found")
+}
+
+// UpdateString is used to update a string value in the save file (This method will return an error if the key does not exist unlike SetString)
+func (d *Data) UpdateString(key string, value string) error {
+	defer d.lockWrite()()
+	if _, ok := d.StringData[key]; ok {
+		d.StringData[key] = value
+		d.changed()
+		return nil
+	}
+	return errors.New("key not found")
+}
+
+// UpdateBool is used to update a bool value in the save file (This method will return an error if the key does not exist unlike SetBool)
+func (d *Data) UpdateBool(key string, value bool) error {
+	defer d.lockWrite()()
+	if _, ok := d.BoolData[key]; ok {
+		d.BoolData[key] = value
+		d.changed()
+		return nil
+	}
+	return errors.New("key not found")
+}
+
+// GetInts is used to get all int values from the save file
+func (d *Data) GetInts() map[string]int {
+	return d.IntData
+}
+
+// GetFloats is used to get all float values from the save file
+func (d *Data) GetFloats() map[string]float64 {
+	return d.FloatData
+}
+
+// GetStrings is used to get all string values from the save file
+func (d *Data) GetStrings() map[string]string {
+	return d.StringData
+}
+
+// GetBools is used to get all bool values from the save file
+func (d *Data) GetBools() map[string]bool {
+	return d.BoolData
+}
+
+// GetIntKeys is used to get all int keys from the save file
+func (d *Data) GetIntKeys() []string {
+	defer d.lockRead()()
+	var keys []string
+	for key := range d.IntData {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// GetFloatKeys is used to get all float keys from the save file
+func (d *Data) GetFloatKeys() []string {
+	defer d.lockRead()()
+	var keys []string
+	for key := range d.FloatData {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// GetStringKeys is used to get all string keys from the save file
+func (d *Data) GetStringKeys() []string {
+	defer d.lockRead()()
+	var keys []string
+	for key := range d.StringData {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// GetBoolKeys is used to get all bool keys from the save file
+func (d *Data) GetBoolKeys() []string {
+	defer d.lockRead()()
+	var keys []string
+	for key := range d.BoolData {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,297 @@
+package NFSave
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"image"
+	"image/png"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// SlotsFolder is the folder in the Store that holds a folder for each save slot
+const SlotsFolder = "slots"
+
+const slotInfoFile = "slot.json"
+const slotSaveFile = "save" + Extension
+const slotThumbnailFile = "thumbnail.png"
+
+// ThumbnailWidth is the width slot thumbnails are scaled down to, keeping the aspect ratio of the capture
+var ThumbnailWidth = 320
+
+var validSlotID = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+
+// SlotInfo is the metadata of a save slot, it is kept next to the save so slots can be listed without decoding their saves
+type SlotInfo struct {
+	ID string `json:"ID"`
+	// Name is the label the player gave the slot, it can be changed with RenameSlot
+	Name     string        `json:"Name"`
+	Scene    string        `json:"Scene"`
+	Chapter  string        `json:"Chapter,omitempty"`
+	Playtime time.Duration `json:"Playtime"`
+	Time     time.Time     `json:"Saved At"`
+}
+
+// slotPath returns the name in the Store of a file in the slot's folder
+func slotPath(id string, file string) string {
+	return path.Join(SlotsFolder, id, file)
+}
+
+func checkSlotID(id string) error {
+	if !validSlotID.MatchString(id) {
+		return NFError.NewErrInvalidArgument("slot", "slot IDs can only contain letters, numbers, _ and -")
+	}
+	return nil
+}
+
+// compareSlotIDs sorts numbered slots by their number and puts them before named slots
+func compareSlotIDs(a, b string) int {
+	aNumber, aErr := strconv.Atoi(a)
+	bNumber, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return aNumber - bNumber
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// ListSlots returns the metadata of every save slot, numbered slots come first in order
+func ListSlots() ([]SlotInfo, error) {
+	entries, err := Store.List(SlotsFolder)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []SlotInfo{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	slots := make([]SlotInfo, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir {
+			continue
+		}
+		info, err := GetSlot(entry.Name)
+		if err != nil {
+			continue
+		}
+		slots = append(slots, info)
+	}
+	slices.SortFunc(slots, func(a, b SlotInfo) int {
+		return compareSlotIDs(a.ID, b.ID)
+	})
+	return slots, nil
+}
+
+// GetSlot returns the metadata of a save slot
+func GetSlot(id string) (SlotInfo, error) {
+	if err := checkSlotID(id); err != nil {
+		return SlotInfo{}, err
+	}
+	infoBytes, err := Store.Read(slotPath(id, slotInfoFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return SlotInfo{}, NFError.NewErrNotFound("no save in slot " + id)
+	} else if err != nil {
+		return SlotInfo{}, err
+	}
+	info := SlotInfo{}
+	err = json.Unmarshal(infoBytes, &info)
+	if err != nil {
+		return SlotInfo{}, err
+	}
+	info.ID = id
+	return info, nil
+}
+
+// SlotExists returns true if there is a save in the slot
+func SlotExists(id string) bool {
+	_, err := GetSlot(id)
+	return err == nil
+}
+
+// NextSlotID returns the lowest numbered slot that is empty
+func NextSlotID() string {
+	for i := 1; ; i++ {
+		id := strconv.Itoa(i)
+		if !SlotExists(id) {
+			return id
+		}
+	}
+}
+
+// WriteSlot saves to the slot, creating it or overwriting the save already in it
+//
+// The thumbnail is usually a capture of the window canvas and is scaled down to ThumbnailWidth, it can be nil.
+// A slot that is overwritten keeps the name the player gave it
+func WriteSlot(id string, s *Save, thumbnail image.Image) (SlotInfo, error) {
+	return writeSlot(id, "", s, thumbnail)
+}
+
+// writeSlot is WriteSlot with a name that replaces the slot's name, an empty name keeps it
+func writeSlot(id, name string, s *Save, thumbnail image.Image) (SlotInfo, error) {
+	if err := checkSlotID(id); err != nil {
+		return SlotInfo{}, err
+	}
+	encoded, err := encodeSlot(s, thumbnail)
+	if err != nil {
+		return SlotInfo{}, err
+	}
+	return encoded.write(id, name)
+}
+
+// encodedSlot is a save encoded for a slot, writing it does not touch the save so it can be done on another goroutine
+type encodedSlot struct {
+	save      []byte
+	info      SlotInfo
+	thumbnail image.Image
+}
+
+// encodeSlot encodes the save and the info shown for its slot
+func encodeSlot(s *Save, thumbnail image.Image) (encodedSlot, error) {
+	saveBytes, err := s.encode()
+	if err != nil {
+		return encodedSlot{}, err
+	}
+	info := SlotInfo{Name: s.Name, Scene: s.Scene, Chapter: s.Chapter, Playtime: s.Playtime, Time: s.Time}
+	return encodedSlot{save: saveBytes, info: info, thumbnail: thumbnail}, nil
+}
+
+// write writes the encoded save to the slot, see writeSlot for the name
+//
+// The save already in the slot is kept as a snapshot first, so if the new save, its info or its thumbnail
+// is cut short LoadSlot still has the last good save to fall back on
+func (e encodedSlot) write(id, name string) (SlotInfo, error) {
+	err := snapshot(path.Join(SlotsFolder, id), max(SlotHistoryLimit, 1))
+	if err != nil {
+		return SlotInfo{}, err
+	}
+	err = Store.Write(slotPath(id, slotSaveFile), e.save)
+	if err != nil {
+		return SlotInfo{}, err
+	}
+
+	info := e.info
+	info.ID = id
+	if name != "" {
+		info.Name = name
+	} else if old, err := GetSlot(id); err == nil && old.Name != "" {
+		info.Name = old.Name
+	}
+	if info.Name == "" {
+		info.Name = "Slot " + id
+	}
+	infoBytes, err := json.MarshalIndent(info, "", "    ")
+	if err != nil {
+		return SlotInfo{}, err
+	}
+	err = Store.Write(slotPath(id, slotInfoFile), infoBytes)
+	if err != nil {
+		return SlotInfo{}, err
+	}
+
+	if e.thumbnail == nil {
+		return info, Store.Remove(slotPath(id, slotThumbnailFile))
+	}
+	var thumbnailBytes bytes.Buffer
+	err = png.Encode(&thumbnailBytes, scaleThumbnail(e.thumbnail))
+	if err != nil {
+		return info, err
+	}
+	return info, Store.Write(slotPath(id, slotThumbnailFile), thumbnailBytes.Bytes())
+}
+
+// LoadSlot loads the save in the slot, it does not make it the active save
+//
+// If the slot's save is missing or corrupt, such as after a crash while it was written, the last good save in the slot is
+// returned instead along with an error matching NFError.ErrSaveRecovered, the same as LoadNamed
+func LoadSlot(id string) (*Save, error) {
+	if err := checkSlotID(id); err != nil {
+		return nil, err
+	}
+	fileBytes, err := Store.Read(slotPath(id, slotSaveFile))
+	if err == nil {
+		var save *Save
+		save, err = decode(fileBytes)
+		if err == nil {
+			return save, nil
+		}
+	}
+	if !recoverable(err) {
+		return nil, err
+	}
+	save, recoverErr := recoverSave(path.Join(SlotsFolder, id), err)
+	if save == nil && errors.Is(err, fs.ErrNotExist) {
+		return nil, NFError.NewErrNotFound("no save in slot " + id)
+	}
+	return save, recoverErr
+}
+
+// DeleteSlot removes the slot and everything in it
+func DeleteSlot(id string) error {
+	if err := checkSlotID(id); err != nil {
+		return err
+	}
+	if !SlotExists(id) {
+		return NFError.NewErrNotFound("no save in slot " + id)
+	}
+	return Store.Remove(path.Join(SlotsFolder, id))
+}
+
+// RenameSlot changes the name shown for the slot
+func RenameSlot(id, name string) error {
+	info, err := GetSlot(id)
+	if err != nil {
+		return err
+	}
+	info.Name = name
+	infoBytes, err := json.MarshalIndent(info, "", "    ")
+	if err != nil {
+		return err
+	}
+	return Store.Write(slotPath(id, slotInfoFile), infoBytes)
+}
+
+// SlotThumbnail returns the slot's thumbnail, or false if it has none
+func SlotThumbnail(id string) (image.Image, bool) {
+	if checkSlotID(id) != nil {
+		return nil, false
+	}
+	thumbnailBytes, err := Store.Read(slotPath(id, slotThumbnailFile))
+	if err != nil {
+		return nil, false
+	}
+	thumbnail, err := png.Decode(bytes.NewReader(thumbnailBytes))
+	if err != nil {
+		return nil, false
+	}
+	return thumbnail, true
+}
+
+// scaleThumbnail scales the image down to ThumbnailWidth with nearest neighbour sampling
+func scaleThumbnail(src image.Image) image.Image {
+	bounds := src.Bounds()
+	if ThumbnailWidth <= 0 || bounds.Dx() <= ThumbnailWidth {
+		return src
+	}
+	height := max(1, bounds.Dy()*ThumbnailWidth/bounds.Dx())
+	dst := image.NewRGBA(image.Rect(0, 0, ThumbnailWidth, height))
+	for y := 0; y < height; y++ {
+		srcY := bounds.Min.Y + y*bounds.Dy()/height
+		for x := 0; x < ThumbnailWidth; x++ {
+			dst.Set(x, y, src.At(bounds.Min.X+x*bounds.Dx()/ThumbnailWidth, srcY))
+		}
+	}
+	return dst
+}
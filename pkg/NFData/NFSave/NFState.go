@@ -0,0 +1,137 @@
+package NFSave
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"log"
+	"slices"
+	"sync"
+)
+
+// StateProvider is a part of the game that keeps state outside the typed data of a save, such as overlays or audio
+//
+// When the active save is written each registered provider is asked for its state, which is kept in a section of the save
+// under the name it was registered with. When a save is resumed each provider is given its section back
+type StateProvider interface {
+	// SaveState returns the state to keep in the save, it is encoded as JSON
+	SaveState() (interface{}, error)
+	// LoadState restores the state from a save, data is nil if the save has no section for the provider,
+	// which is the case for saves written before it was registered
+	LoadState(data json.RawMessage) error
+}
+
+// StateFuncs is a StateProvider made from two functions, so a provider does not need its own type
+type StateFuncs struct {
+	Save func() (interface{}, error)
+	Load func(data json.RawMessage) error
+}
+
+func (f StateFuncs) SaveState() (interface{}, error) {
+	return f.Save()
+}
+
+func (f StateFuncs) LoadState(data json.RawMessage) error {
+	return f.Load(data)
+}
+
+var stateProviders = map[string]StateProvider{}
+var stateProvidersLock sync.RWMutex
+
+// RegisterStateProvider registers the provider under the name its section is saved with, names must be unique
+func RegisterStateProvider(name string, provider StateProvider) error {
+	stateProvidersLock.Lock()
+	defer stateProvidersLock.Unlock()
+	if _, ok := stateProviders[name]; ok {
+		return NFError.NewErrKeyAlreadyExists("state provider " + name)
+	}
+	stateProviders[name] = provider
+	return nil
+}
+
+// UnregisterStateProvider removes the provider registered under the name, sections it already wrote are kept in saves
+func UnregisterStateProvider(name string) {
+	stateProvidersLock.Lock()
+	defer stateProvidersLock.Unlock()
+	delete(stateProviders, name)
+}
+
+// GetStateProviders returns the names of the registered providers in the order they are saved and restored
+func GetStateProviders() []string {
+	stateProvidersLock.RLock()
+	defer stateProvidersLock.RUnlock()
+	names := make([]string, 0, len(stateProviders))
+	for name := range stateProviders {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// getStateProvider returns the provider registered under the name
+func getStateProvider(name string) (StateProvider, bool) {
+	stateProvidersLock.RLock()
+	defer stateProvidersLock.RUnlock()
+	provider, ok := stateProviders[name]
+	return provider, ok
+}
+
+// CaptureState asks every registered provider for its state and keeps it in the save's sections
+//
+// Sections of providers that are not registered are left in the save, so they are not lost by a build without them.
+// A provider that fails keeps its old section and the errors of every failed provider are returned together
+func (s *Save) CaptureState() error {
+	if s.Sections == nil {
+		s.Sections = map[string]json.RawMessage{}
+	}
+	var errs error
+	for _, name := range GetStateProviders() {
+		provider, ok := getStateProvider(name)
+		if !ok {
+			continue
+		}
+		state, err := provider.SaveState()
+		if err == nil {
+			var section []byte
+			section, err = json.Marshal(state)
+			if err == nil {
+				s.Sections[name] = section
+				continue
+			}
+		}
+		errs = errors.Join(errs, fmt.Errorf("saving state of %s: %w", name, err))
+	}
+	return errs
+}
+
+// RestoreState gives every registered provider its section of the save, providers the save has no section for are given nil
+//
+// Every provider is restored even if one fails, the errors of every failed provider are returned together.
+// A section no provider is registered for is an ErrMissingStateProvider, its state would otherwise be silently dropped
+func (s *Save) RestoreState() error {
+	var errs error
+	sections := make([]string, 0, len(s.Sections))
+	for name := range s.Sections {
+		sections = append(sections, name)
+	}
+	slices.Sort(sections)
+	for _, name := range sections {
+		if _, ok := getStateProvider(name); !ok {
+			err := NFError.NewErrMissingStateProvider(name)
+			log.Println(err)
+			errs = errors.Join(errs, err)
+		}
+	}
+	for _, name := range GetStateProviders() {
+		provider, ok := getStateProvider(name)
+		if !ok {
+			continue
+		}
+		err := provider.LoadState(s.Sections[name])
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("restoring state of %s: %w", name, err))
+		}
+	}
+	return errs
+}
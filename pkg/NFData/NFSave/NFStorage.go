@@ -0,0 +1,285 @@
+package NFSave
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StorageEntry is a file or folder in a Storage
+type StorageEntry struct {
+	Name  string
+	IsDir bool
+	Size  int64
+	// ModTime is when the file was last written, it is zero if the storage can not tell
+	ModTime time.Time
+}
+
+// Storage is where saves, slots and snapshots are kept, games can set their own with SetStorage
+//
+// Names are slash separated paths relative to the root of the storage. Read, Stat, List and Rename
+// must return an error matching fs.ErrNotExist when there is nothing at the name
+type Storage interface {
+	// Read returns the contents of the file
+	Read(name string) ([]byte, error)
+	// Write creates or replaces the file, creating any folders it is in.
+	// The file should be replaced in one step so a crash while writing never leaves it half written
+	Write(name string, data []byte) error
+	// Remove deletes the file, or the folder and everything in it, it does nothing if there is nothing at the name
+	Remove(name string) error
+	// Rename moves the file, replacing any file at the new name and creating any folders it is in
+	Rename(oldName, newName string) error
+	// Stat returns the file or folder at the name
+	Stat(name string) (StorageEntry, error)
+	// List returns the files and folders directly inside the folder
+	List(dir string) ([]StorageEntry, error)
+}
+
+// Store is the storage everything in NFSave is read from and written to,
+// by default it is a FileStorage in Directory so games that only set Directory keep working
+var Store Storage = NewFileStorage("")
+
+// GetStorage and SetStorage are used to get and set the storage saves are kept in
+func GetStorage() Storage {
+	return Store
+}
+
+// SetStorage is used to set the storage saves are kept in
+func SetStorage(storage Storage) {
+	Store = storage
+}
+
+// notExist returns an error for a missing name that matches fs.ErrNotExist
+func notExist(op, name string) error {
+	return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+// FileStorage keeps saves in a folder on disk
+type FileStorage struct {
+	// Root is the folder the names are relative to, if it is empty Directory is used
+	Root string
+}
+
+// NewFileStorage creates a storage in the root folder, an empty root follows Directory
+func NewFileStorage(root string) *FileStorage {
+	return &FileStorage{Root: root}
+}
+
+// path returns the path on disk of the name
+func (f *FileStorage) path(name string) string {
+	root := f.Root
+	if root == "" {
+		root = Directory
+	}
+	return filepath.Join(root, filepath.FromSlash(name))
+}
+
+func (f *FileStorage) Read(name string) ([]byte, error) {
+	return os.ReadFile(f.path(name))
+}
+
+// Write writes the data to a temporary file next to the file and renames it over the file once it is on disk,
+// so a crash while writing leaves the old file as it was
+func (f *FileStorage) Write(name string, data []byte) error {
+	dir, base := filepath.Split(f.path(name))
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(dir, "."+base+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = temp.Write(data)
+	if err == nil {
+		err = temp.Sync()
+	}
+	if err = errors.Join(err, temp.Close()); err == nil {
+		err = os.Chmod(temp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), f.path(name))
+	}
+	if err != nil {
+		_ = os.Remove(temp.Name())
+	}
+	return err
+}
+
+func (f *FileStorage) Remove(name string) error {
+	return os.RemoveAll(f.path(name))
+}
+
+func (f *FileStorage) Rename(oldName, newName string) error {
+	err := os.MkdirAll(filepath.Dir(f.path(newName)), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.path(oldName), f.path(newName))
+}
+
+func (f *FileStorage) Stat(name string) (StorageEntry, error) {
+	info, err := os.Stat(f.path(name))
+	if err != nil {
+		return StorageEntry{}, err
+	}
+	return StorageEntry{Name: path.Base(name), IsDir: info.IsDir(), Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+func (f *FileStorage) List(dir string) ([]StorageEntry, error) {
+	entries, err := os.ReadDir(f.path(dir))
+	if err != nil {
+		return nil, err
+	}
+	list := make([]StorageEntry, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		list = append(list, StorageEntry{Name: entry.Name(), IsDir: entry.IsDir(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+	return list, nil
+}
+
+// memoryFile is a file kept by a MemoryStorage
+type memoryFile struct {
+	data    []byte
+	modTime time.Time
+}
+
+// MemoryStorage keeps saves in memory, they are lost when the game closes so it is meant for tests and previews
+//
+// Folders are not stored, a folder exists while there are files in it
+type MemoryStorage struct {
+	lock  sync.RWMutex
+	files map[string]memoryFile
+}
+
+// NewMemoryStorage creates an empty storage in memory
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{files: map[string]memoryFile{}}
+}
+
+// clean turns the name in to the key it is stored under
+func (m *MemoryStorage) clean(name string) string {
+	name = strings.Trim(path.Clean("/"+name), "/")
+	return name
+}
+
+// isDir returns true if any file is inside the folder, the caller must hold the lock
+func (m *MemoryStorage) isDir(name string) bool {
+	prefix := name + "/"
+	if name == "" {
+		prefix = ""
+	}
+	for key := range m.files {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemoryStorage) Read(name string) ([]byte, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	file, ok := m.files[m.clean(name)]
+	if !ok {
+		return nil, notExist("read", name)
+	}
+	return slices.Clone(file.data), nil
+}
+
+func (m *MemoryStorage) Write(name string, data []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.files[m.clean(name)] = memoryFile{data: slices.Clone(data), modTime: time.Now()}
+	return nil
+}
+
+func (m *MemoryStorage) Remove(name string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	name = m.clean(name)
+	for key := range m.files {
+		if key == name || name == "" || strings.HasPrefix(key, name+"/") {
+			delete(m.files, key)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStorage) Rename(oldName, newName string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	oldName, newName = m.clean(oldName), m.clean(newName)
+	file, ok := m.files[oldName]
+	if !ok {
+		return notExist("rename", oldName)
+	}
+	delete(m.files, oldName)
+	m.files[newName] = file
+	return nil
+}
+
+func (m *MemoryStorage) Stat(name string) (StorageEntry, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	name = m.clean(name)
+	if file, ok := m.files[name]; ok {
+		return StorageEntry{Name: path.Base(name), Size: int64(len(file.data)), ModTime: file.modTime}, nil
+	}
+	if m.isDir(name) {
+		return StorageEntry{Name: path.Base(name), IsDir: true}, nil
+	}
+	return StorageEntry{}, notExist("stat", name)
+}
+
+func (m *MemoryStorage) List(dir string) ([]StorageEntry, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	dir = m.clean(dir)
+	prefix := dir + "/"
+	if dir == "" {
+		prefix = ""
+	}
+	entries := map[string]StorageEntry{}
+	for key, file := range m.files {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok || rest == "" {
+			continue
+		}
+		if child, _, nested := strings.Cut(rest, "/"); nested {
+			entries[child] = StorageEntry{Name: child, IsDir: true}
+		} else {
+			entries[rest] = StorageEntry{Name: rest, Size: int64(len(file.data)), ModTime: file.modTime}
+		}
+	}
+	if len(entries) == 0 && dir != "" {
+		return nil, notExist("list", dir)
+	}
+	list := make([]StorageEntry, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, entry)
+	}
+	slices.SortFunc(list, func(a, b StorageEntry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return list, nil
+}
+
+// exists returns true if there is a file or folder at the name in the Store
+func exists(name string) (bool, error) {
+	_, err := Store.Stat(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,164 @@
+package NFSave
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+)
+
+// useMemoryStorage sets the Store to an empty MemoryStorage with history, encryption and the encoding at their defaults,
+// they are put back once the test ends
+func useMemoryStorage(t *testing.T) *MemoryStorage {
+	t.Helper()
+	store, history, encryption, encoding := Store, SaveHistory, SaveEncryption, SaveEncoding
+	historyLimit, slotHistoryLimit := HistoryLimit, SlotHistoryLimit
+	t.Cleanup(func() {
+		Store, SaveHistory, SaveEncryption, SaveEncoding = store, history, encryption, encoding
+		HistoryLimit, SlotHistoryLimit = historyLimit, slotHistoryLimit
+	})
+	memory := NewMemoryStorage()
+	Store, SaveHistory, SaveEncryption, SaveEncoding = memory, true, false, ""
+	return memory
+}
+
+// write writes the files to the storage and fails the test if any can not be written
+func write(t *testing.T, storage Storage, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if err := storage.Write(name, []byte(name)); err != nil {
+			t.Fatalf("Write(%q) returned an error: %v", name, err)
+		}
+	}
+}
+
+// names returns the names of the entries and whether each is a folder
+func names(entries []StorageEntry) map[string]bool {
+	list := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		list[entry.Name] = entry.IsDir
+	}
+	return list
+}
+
+func TestMemoryStorageList(t *testing.T) {
+	storage := NewMemoryStorage()
+	write(t, storage, "a/save.novella", "a/history/save-000001.novellahistory", "b/save.novella", "top.novella")
+	tests := []struct {
+		dir  string
+		want map[string]bool
+	}{
+		{"", map[string]bool{"a": true, "b": true, "top.novella": false}},
+		{"/", map[string]bool{"a": true, "b": true, "top.novella": false}},
+		{"a", map[string]bool{"save.novella": false, "history": true}},
+		{"a/", map[string]bool{"save.novella": false, "history": true}},
+		{"./a/history", map[string]bool{"save-000001.novellahistory": false}},
+	}
+	for _, test := range tests {
+		entries, err := storage.List(test.dir)
+		if err != nil {
+			t.Errorf("List(%q) returned an error: %v", test.dir, err)
+			continue
+		}
+		if got := names(entries); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("List(%q) = %v, want %v", test.dir, got, test.want)
+		}
+	}
+	entries, _ := storage.List("b")
+	if len(entries) != 1 || entries[0].Size != int64(len("b/save.novella")) || entries[0].ModTime.IsZero() {
+		t.Errorf("List(%q) = %+v, want the size and time the file was written", "b", entries)
+	}
+	for _, dir := range []string{"missing", "a/save.novella", "top"} {
+		if _, err := storage.List(dir); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("List(%q) returned %v, want an fs.ErrNotExist", dir, err)
+		}
+	}
+	if entries, err := NewMemoryStorage().List(""); err != nil || len(entries) != 0 {
+		t.Errorf("List of the root of an empty storage = %v, %v, want no entries", entries, err)
+	}
+}
+
+func TestMemoryStorageRename(t *testing.T) {
+	storage := NewMemoryStorage()
+	write(t, storage, "a/save.novella", "b/save.novella")
+	if err := storage.Rename("a/save.novella", "a/history/save-000001.novellahistory"); err != nil {
+		t.Fatalf("Rename returned an error: %v", err)
+	}
+	if _, err := storage.Read("a/save.novella"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Read of the old name returned %v, want an fs.ErrNotExist", err)
+	}
+	if data, err := storage.Read("a/history/save-000001.novellahistory"); err != nil || string(data) != "a/save.novella" {
+		t.Errorf("Read of the new name = %q, %v, want the renamed file", data, err)
+	}
+	//Renaming over a file replaces it
+	if err := storage.Rename("b/save.novella", "a/history/save-000001.novellahistory"); err != nil {
+		t.Fatalf("Rename over a file returned an error: %v", err)
+	}
+	if data, _ := storage.Read("a/history/save-000001.novellahistory"); string(data) != "b/save.novella" {
+		t.Errorf("Rename over a file left %q, want it replaced", data)
+	}
+	if _, err := storage.Stat("b"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat of a folder with nothing left in it returned %v, want an fs.ErrNotExist", err)
+	}
+	if err := storage.Rename("missing", "other"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Rename of a missing file returned %v, want an fs.ErrNotExist", err)
+	}
+}
+
+func TestMemoryStorageRemove(t *testing.T) {
+	storage := NewMemoryStorage()
+	write(t, storage, "a/save.novella", "a/history/save-000001.novellahistory", "ab/save.novella", "b/save.novella")
+	if err := storage.Remove("a"); err != nil {
+		t.Fatalf("Remove of a folder returned an error: %v", err)
+	}
+	for _, name := range []string{"a", "a/save.novella", "a/history/save-000001.novellahistory"} {
+		if _, err := storage.Stat(name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Stat(%q) after its folder was removed returned %v, want an fs.ErrNotExist", name, err)
+		}
+	}
+	if _, err := storage.Stat("ab/save.novella"); err != nil {
+		t.Errorf("Remove of a folder removed a folder that starts with the same name: %v", err)
+	}
+	if err := storage.Remove("b/save.novella"); err != nil {
+		t.Fatalf("Remove of a file returned an error: %v", err)
+	}
+	if _, err := storage.Read("b/save.novella"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Read of a removed file returned %v, want an fs.ErrNotExist", err)
+	}
+	if err := storage.Remove("missing"); err != nil {
+		t.Errorf("Remove of a missing name returned %v, want nothing to happen", err)
+	}
+}
+
+func TestMemoryStorageStat(t *testing.T) {
+	storage := NewMemoryStorage()
+	write(t, storage, "a/save.novella")
+	tests := []struct {
+		name  string
+		isDir bool
+	}{
+		{"a", true},
+		{"a/save.novella", false},
+	}
+	for _, test := range tests {
+		entry, err := storage.Stat(test.name)
+		if err != nil || entry.IsDir != test.isDir {
+			t.Errorf("Stat(%q) = %+v, %v, want IsDir %v", test.name, entry, err, test.isDir)
+		}
+	}
+	if _, err := storage.Stat("a/missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat of a missing name returned %v, want an fs.ErrNotExist", err)
+	}
+}
+
+func TestMemoryStorageCopies(t *testing.T) {
+	storage := NewMemoryStorage()
+	data := []byte("save")
+	_ = storage.Write("save", data)
+	data[0] = 'X'
+	read, _ := storage.Read("save")
+	read[1] = 'X'
+	if again, _ := storage.Read("save"); string(again) != "save" {
+		t.Errorf("Read = %q after the written and read bytes were changed, want the storage to keep its own copy", again)
+	}
+}
@@ -0,0 +1,491 @@
+package NFScript
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go.novellaforge.dev/novellaforge/pkg/NFData"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFFunction"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFLayout"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFScene"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Extension is the file extension used for screenplay scripts
+const Extension = ".NFScript"
+
+// A script is a plain text screenplay that compiles in to one scene per line of dialogue, narration or choice.
+//
+// Each line is one statement, blank lines and lines starting with # are ignored:
+//
+//	scene Prologue                  names the generated scenes Prologue_001, Prologue_002... (defaults to the file name)
+//	label start                     marks a place that can be jumped to
+//	Alice: Hello {Global.Player}!   a line of dialogue, text can use NFExpression interpolation
+//	> The wind howls.               a line of narration
+//	set Global.Gold = Global.Gold + 10
+//	jump start                      continues from a label
+//	goto MainMenu                   changes to a scene that is not part of the script
+//	if Global.Gold > 5 jump rich    jumps (or goes to a scene) only when the expression is true
+//	choice Where do you go?         starts a choice, the prompt is optional
+//	* Go left -> left               an option of the choice that jumps to a label
+//	* Go right -> right if Global.Brave
+//
+// Picked options are recorded so they can be checked with Save.Chosen.<scene>.<option>, where the option is its text
+// in lower case with everything but letters and digits replaced by _, such as Save.Chosen.Prologue_003.go_left.
+// Options with no letters or digits, or the same text as an option before them, are named option_<n> counting from 1
+//
+// Set, jump, goto and if statements run when the player continues from the line before them
+
+type statementKind int
+
+const (
+	statementSpeech statementKind = iota
+	statementNarration
+	statementChoice
+	statementLabel
+	statementSet
+	statementJump
+	statementGoto
+	statementIf
+)
+
+type option struct {
+	Text      string
+	Target    string
+	Condition string
+	Line      int
+}
+
+type statement struct {
+	Kind statementKind
+	Line int
+	// Speaker is the name of the speaker for dialogue
+	Speaker string
+	// Text is the dialogue, narration or choice prompt
+	Text string
+	// Target is the label name for label and jump statements, or the scene name for goto statements
+	Target string
+	// Goto is true when an if statement changes scene instead of jumping to a label
+	Goto bool
+	// Reference and Expression are used by set and if statements
+	Reference  NFData.NFReference
+	Expression string
+	Options    []option
+}
+
+// isBeat returns true for the statements that are shown to the player and get their own scene
+func (s statement) isBeat() bool {
+	return s.Kind == statementSpeech || s.Kind == statementNarration || s.Kind == statementChoice
+}
+
+// Script is a parsed screenplay ready to be compiled in to scenes
+type Script struct {
+	// File is the name of the script file used in errors
+	File string
+	// Name is the prefix of the generated scene names
+	Name       string
+	statements []statement
+	labels     map[string]int
+}
+
+var (
+	namePattern    = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+	setPattern     = regexp.MustCompile(`^set\s+(\w+)\.(\S+)\s*=\s*(.+)$`)
+	ifPattern      = regexp.MustCompile(`^if\s+(.+?)\s+(jump|goto)\s+(\S+)$`)
+	optionPattern  = regexp.MustCompile(`^\*\s*(.+?)\s*->\s*(\S+)(?:\s+if\s+(.+))?$`)
+	speakerPattern = regexp.MustCompile(`^([^:]+?):\s*(.+)$`)
+)
+
+// Parse reads a script, every line with an error is reported and the errors are joined
+func Parse(file string, r io.Reader) (*Script, error) {
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	script := &Script{File: file, Name: name, labels: make(map[string]int)}
+	var fullErr error
+	lineErr := func(line int, reason string) {
+		fullErr = errors.Join(fullErr, NFError.NewErrScriptParse(file, line, reason))
+	}
+	checkExpression := func(line int, expression string) {
+		if err := NFData.NewExpression(expression).Check(); err != nil {
+			lineErr(line, err.Error())
+		}
+	}
+	seenBeat := false
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keyword, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+		s := statement{Line: lineNumber}
+		switch {
+		case strings.HasPrefix(line, "*"):
+			if len(script.statements) == 0 || script.statements[len(script.statements)-1].Kind != statementChoice {
+				lineErr(lineNumber, "options must follow a choice")
+				continue
+			}
+			match := optionPattern.FindStringSubmatch(line)
+			if match == nil {
+				lineErr(lineNumber, "options should look like: * Text -> label")
+				continue
+			}
+			if match[3] != "" {
+				checkExpression(lineNumber, match[3])
+			}
+			choice := &script.statements[len(script.statements)-1]
+			choice.Options = append(choice.Options, option{Text: match[1], Target: match[2], Condition: match[3], Line: lineNumber})
+			continue
+		case strings.HasPrefix(line, ">"):
+			s.Kind = statementNarration
+			s.Text = strings.TrimSpace(strings.TrimPrefix(line, ">"))
+		case keyword == "scene":
+			if seenBeat {
+				lineErr(lineNumber, "scene must come before the first line of dialogue")
+				continue
+			}
+			if !namePattern.MatchString(rest) {
+				lineErr(lineNumber, "invalid scene name "+rest)
+				continue
+			}
+			script.Name = rest
+			continue
+		case keyword == "label":
+			if !namePattern.MatchString(rest) {
+				lineErr(lineNumber, "invalid label name "+rest)
+				continue
+			}
+			if _, ok := script.labels[rest]; ok {
+				lineErr(lineNumber, "label "+rest+" is already defined")
+				continue
+			}
+			s.Kind = statementLabel
+			s.Target = rest
+			script.labels[rest] = len(script.statements)
+		case keyword == "jump" || keyword == "goto":
+			if rest == "" || strings.Contains(rest, " ") {
+				lineErr(lineNumber, keyword+" needs exactly one target")
+				continue
+			}
+			s.Kind = statementJump
+			if keyword == "goto" {
+				s.Kind = statementGoto
+			}
+			s.Target = rest
+		case keyword == "set":
+			match := setPattern.FindStringSubmatch(line)
+			if match == nil {
+				lineErr(lineNumber, "set should look like: set Global.Key = expression")
+				continue
+			}
+			if !slices.Contains(NFData.GetRefTypes(), NFData.Type(match[1])) {
+				lineErr(lineNumber, "unknown location "+match[1])
+				continue
+			}
+			checkExpression(lineNumber, match[3])
+			s.Kind = statementSet
+			s.Reference = NFData.NewRef(NFData.Type(match[1]), match[2])
+			s.Expression = match[3]
+		case keyword == "if":
+			match := ifPattern.FindStringSubmatch(line)
+			if match == nil {
+				lineErr(lineNumber, "if should look like: if expression jump label")
+				continue
+			}
+			checkExpression(lineNumber, match[1])
+			s.Kind = statementIf
+			s.Expression = match[1]
+			s.Goto = match[2] == "goto"
+			s.Target = match[3]
+		case keyword == "choice":
+			s.Kind = statementChoice
+			s.Text = rest
+		default:
+			match := speakerPattern.FindStringSubmatch(line)
+			if match == nil {
+				lineErr(lineNumber, "unknown statement, dialogue should look like: Speaker: text")
+				continue
+			}
+			s.Kind = statementSpeech
+			s.Speaker = strings.TrimSpace(match[1])
+			s.Text = match[2]
+		}
+		seenBeat = seenBeat || s.isBeat()
+		script.statements = append(script.statements, s)
+	}
+	if err := scanner.Err(); err != nil {
+		fullErr = errors.Join(fullErr, err)
+	}
+	for _, s := range script.statements {
+		if s.Kind == statementChoice && len(s.Options) == 0 {
+			lineErr(s.Line, "choice has no options")
+		}
+	}
+	if !namePattern.MatchString(script.Name) {
+		lineErr(1, "the file name "+script.Name+" can not be used as a scene name, add a scene statement")
+	}
+	return script, fullErr
+}
+
+// sceneName returns the name of the scene generated for the beat at index
+func (s *Script) sceneName(index int) string {
+	beat := 0
+	for i := 0; i <= index; i++ {
+		if s.statements[i].isBeat() {
+			beat++
+		}
+	}
+	return fmt.Sprintf("%s_%03d", s.Name, beat)
+}
+
+// Compile compiles the script in to scenes, the first scene is where the script starts
+func (s *Script) Compile() ([]*NFScene.Scene, error) {
+	var fullErr error
+	scenes := make([]*NFScene.Scene, 0)
+	for i, st := range s.statements {
+		if !st.isBeat() {
+			continue
+		}
+		if len(scenes) == 0 && i > 0 && slices.ContainsFunc(s.statements[:i], func(st statement) bool { return st.Kind != statementLabel }) {
+			log.Println("Script", s.File, "has statements before its first line, they only run when jumped to")
+		}
+		scene, err := s.compileBeat(i)
+		if err != nil {
+			fullErr = errors.Join(fullErr, err)
+			continue
+		}
+		scenes = append(scenes, scene)
+	}
+	if len(scenes) == 0 && fullErr == nil {
+		fullErr = NFError.NewErrScriptParse(s.File, 1, "the script has no dialogue, narration or choices")
+	}
+	return scenes, fullErr
+}
+
+func (s *Script) compileBeat(index int) (*NFScene.Scene, error) {
+	st := s.statements[index]
+	children := make([]*NFWidget.Widget, 0)
+	switch st.Kind {
+	case statementSpeech:
+		children = append(children, textBox(st.Speaker, st.Text))
+	case statementNarration:
+		children = append(children, textBox("", st.Text))
+	case statementChoice:
+		options := make([]interface{}, 0, len(st.Options))
+		ids := make([]string, 0, len(st.Options))
+		for i, o := range st.Options {
+			call, err := s.jumpCall(o.Target, o.Line, nil)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, optionID(o.Text, i, ids))
+			choice := map[string]interface{}{"ID": ids[i], "Text": textValue(o.Text)}
+			if call != nil {
+				choice["Function"] = call
+			}
+			if o.Condition != "" {
+				choice["Condition"] = map[string]interface{}{"Expression": o.Condition}
+			}
+			options = append(options, choice)
+		}
+		//The menu is named after its scene so picks can be checked with Save.Chosen.<scene>.<option>, see optionID
+		menu := NFWidget.New("ChoiceMenu", NFWidget.NewChildren(), NFData.NewNFInterfaceMap(
+			NFData.NewKeyVal("Options", options),
+			NFData.NewKeyVal("MenuID", s.sceneName(index)),
+			NFData.NewKeyVal("Prompt", st.Text),
+		))
+		menu.SetName(s.sceneName(index))
+		children = append(children, menu)
+	}
+	if st.Kind != statementChoice {
+		call, err := s.chain(index+1, nil)
+		if err != nil {
+			return nil, err
+		}
+		if call != nil {
+			button := NFWidget.New("Button", NFWidget.NewChildren(), NFData.NewNFInterfaceMap(NFData.NewKeyVal("Text", "Continue")))
+			button.SetName("Continue")
+			button.AddFunction(onTapped(call))
+			children = append(children, button)
+		}
+	}
+	scene := NFScene.New(s.sceneName(index), NFLayout.New("VBox", children, NFData.NewNFInterfaceMap()), NFData.NewNFInterfaceMap())
+	scene.MakeId()
+	return scene, nil
+}
+
+// chain builds the function call that runs the statements from index until the next beat,
+// visited holds the labels already jumped through so jump loops without any dialogue are reported
+func (s *Script) chain(index int, visited []string) (map[string]interface{}, error) {
+	calls := make([]interface{}, 0)
+	done := false
+	for i := index; i < len(s.statements) && !done; i++ {
+		st := s.statements[i]
+		switch st.Kind {
+		case statementLabel:
+			continue
+		case statementSet:
+			calls = append(calls, call("SetVar", map[string]interface{}{
+				"Location": string(st.Reference.Location),
+				"Key":      st.Reference.Key,
+				"Value":    map[string]interface{}{"Expression": st.Expression},
+			}))
+		case statementJump:
+			jump, err := s.jumpCall(st.Target, st.Line, visited)
+			if err != nil {
+				return nil, err
+			}
+			if jump != nil {
+				calls = append(calls, jump)
+			}
+			done = true
+		case statementGoto:
+			calls = append(calls, changeScene(st.Target))
+			done = true
+		case statementIf:
+			var then map[string]interface{}
+			var err error
+			if st.Goto {
+				then = changeScene(st.Target)
+			} else {
+				then, err = s.jumpCall(st.Target, st.Line, visited)
+				if err != nil {
+					return nil, err
+				}
+			}
+			otherwise, err := s.chain(i+1, visited)
+			if err != nil {
+				return nil, err
+			}
+			args := map[string]interface{}{"Condition": map[string]interface{}{"Expression": st.Expression}}
+			if then != nil {
+				args["Then"] = then
+			}
+			if otherwise != nil {
+				args["Else"] = otherwise
+			}
+			calls = append(calls, call("If", args))
+			done = true
+		default:
+			calls = append(calls, changeScene(s.sceneName(i)))
+			done = true
+		}
+	}
+	switch len(calls) {
+	case 0:
+		return nil, nil
+	case 1:
+		return calls[0].(map[string]interface{}), nil
+	default:
+		return call("Sequence", map[string]interface{}{"Functions": calls}), nil
+	}
+}
+
+// jumpCall builds the function call that continues the script from the label
+func (s *Script) jumpCall(label string, line int, visited []string) (map[string]interface{}, error) {
+	index, ok := s.labels[label]
+	if !ok {
+		return nil, NFError.NewErrScriptParse(s.File, line, "unknown label "+label)
+	}
+	if slices.Contains(visited, label) {
+		return nil, NFError.NewErrScriptParse(s.File, line, "jump to "+label+" loops forever without any dialogue")
+	}
+	return s.chain(index, append(slices.Clone(visited), label))
+}
+
+func call(functionType string, args map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"Type": functionType, "Args": args}
+}
+
+func changeScene(scene string) map[string]interface{} {
+	return call("ChangeScene", map[string]interface{}{"Scene": scene})
+}
+
+// onTapped converts a function call in to an OnTapped function for a button
+func onTapped(c map[string]interface{}) *NFFunction.Function {
+	function := NFFunction.New("OnTapped", c["Type"].(string), NFData.NewNFInterfaceFromMap(c["Args"].(map[string]interface{})))
+	function.SetName(c["Type"].(string))
+	return function
+}
+
+// optionID returns the ID an option is recorded with when picked, its text in lower case with every run of anything
+// but letters and digits replaced by _, so it can be used in an NFExpression path.
+// Options that would have no ID or the ID of an option before them are named option_<n> after their place in the choice
+func optionID(text string, index int, taken []string) string {
+	var id strings.Builder
+	gap := false
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if gap && id.Len() > 0 {
+				id.WriteRune('_')
+			}
+			id.WriteRune(r)
+			gap = false
+		} else {
+			gap = true
+		}
+	}
+	if id.Len() == 0 || slices.Contains(taken, id.String()) {
+		return fmt.Sprintf("option_%d", index+1)
+	}
+	return id.String()
+}
+
+// textValue returns the text as is, or as an NFExpression when it uses interpolation
+func textValue(text string) interface{} {
+	if !strings.Contains(text, "{") {
+		return text
+	}
+	return map[string]interface{}{"Expression": "'" + strings.ReplaceAll(text, "'", `\'`) + "'"}
+}
+
+// textBox shows a line in a NarrativeBox so that it is recorded in the backlog, the name plate is only shown for dialogue
+func textBox(speaker, text string) *NFWidget.Widget {
+	box := NFWidget.New("NarrativeBox", NFWidget.NewChildren(), NFData.NewNFInterfaceMap(
+		NFData.NewKeyVal("AllText", []interface{}{textValue(text)}),
+		NFData.NewKeyVal("Name", speaker),
+		NFData.NewKeyVal("HasName", speaker != ""),
+		NFData.NewKeyVal("StateOnTap", false),
+	))
+	box.SetName("Text")
+	return box
+}
+
+// Compile parses and compiles a script in one step
+func Compile(file string, r io.Reader) ([]*NFScene.Scene, error) {
+	script, err := Parse(file, r)
+	if err != nil {
+		return nil, err
+	}
+	return script.Compile()
+}
+
+// CompileFile compiles the script at path
+func CompileFile(path string) ([]*NFScene.Scene, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Compile(path, file)
+}
+
+// SaveAll saves the compiled scenes as .NFScene files in the directory
+func SaveAll(scenes []*NFScene.Scene, directory string) error {
+	var fullErr error
+	for _, scene := range scenes {
+		fullErr = errors.Join(fullErr, scene.Save(directory))
+	}
+	return fullErr
+}
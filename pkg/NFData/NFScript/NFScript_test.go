@@ -0,0 +1,294 @@
+package NFScript
+
+import (
+	"errors"
+	"fmt"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFScene"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFObjects/NFWidget"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// compile compiles a script from its lines and fails the test if it does not compile
+func compile(t *testing.T, lines ...string) []*NFScene.Scene {
+	t.Helper()
+	scenes, err := Compile("Test.NFScript", strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatalf("Compile returned an error: %v", err)
+	}
+	return scenes
+}
+
+// child returns the widget of the scene with the name
+func child(t *testing.T, scene *NFScene.Scene, name string) *NFWidget.Widget {
+	t.Helper()
+	for _, widget := range scene.Layout.Children {
+		if widget.Name == name {
+			return widget
+		}
+	}
+	t.Fatalf("scene %s has no widget named %s", scene.Name, name)
+	return nil
+}
+
+// continueCall returns the function call of the Continue button of the scene, or nil if it has none
+func continueCall(t *testing.T, scene *NFScene.Scene) map[string]interface{} {
+	t.Helper()
+	for _, widget := range scene.Layout.Children {
+		if widget.Name != "Continue" {
+			continue
+		}
+		if len(widget.Functions) != 1 {
+			t.Fatalf("the Continue button of %s has %d functions, want 1", scene.Name, len(widget.Functions))
+		}
+		function := widget.Functions[0]
+		return call(function.Type, map[string]interface{}(function.Args.ToMap()))
+	}
+	return nil
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		line   int
+		reason string
+	}{
+		{"option without a choice", "Alice: Hi\n* Go -> end", 2, "options must follow a choice"},
+		{"option without a target", "choice Go?\n* Left", 2, "options should look like"},
+		{"option with a broken condition", "choice\n* Left -> end if 1 +\nlabel end\n> End", 2, "unexpected end of expression"},
+		{"choice without options", "Alice: Hi\nchoice Where?", 2, "choice has no options"},
+		{"scene after dialogue", "Alice: Hi\nscene Late", 2, "scene must come before"},
+		{"invalid scene name", "scene Two words", 1, "invalid scene name"},
+		{"invalid label name", "label a.b", 1, "invalid label name"},
+		{"duplicate label", "label start\n> Hi\nlabel start", 3, "label start is already defined"},
+		{"jump without a target", "> Hi\njump", 2, "jump needs exactly one target"},
+		{"goto with two targets", "> Hi\ngoto A B", 2, "goto needs exactly one target"},
+		{"malformed set", "set Gold 10", 1, "set should look like"},
+		{"set with an unknown location", "set Player.Gold = 10", 1, "unknown location Player"},
+		{"set with a broken expression", "set Global.Gold = (1", 1, "missing )"},
+		{"malformed if", "if Global.Gold > 5", 1, "if should look like"},
+		{"unknown statement", "Hello there", 1, "unknown statement"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse("Test.NFScript", strings.NewReader(test.script))
+			if !errors.Is(err, NFError.ErrScriptParse) {
+				t.Fatalf("Parse returned %v, want an ErrScriptParse", err)
+			}
+			prefix := fmt.Sprintf("Test.NFScript:%d: ", test.line)
+			if !strings.Contains(err.Error(), prefix) || !strings.Contains(err.Error(), test.reason) {
+				t.Errorf("Parse returned %q, want line %d to report %q", err.Error(), test.line, test.reason)
+			}
+		})
+	}
+}
+
+func TestParseReportsEveryLine(t *testing.T) {
+	_, err := Parse("Test.NFScript", strings.NewReader("Hello\n> Fine\nset Gold 1\nchoice"))
+	if err == nil {
+		t.Fatal("Parse did not return an error")
+	}
+	for _, want := range []string{"Test.NFScript:1: ", "Test.NFScript:3: ", "Test.NFScript:4: "} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse returned %q, want it to report %q", err.Error(), want)
+		}
+	}
+	if strings.Contains(err.Error(), "Test.NFScript:2: ") {
+		t.Errorf("Parse returned %q, want line 2 to have no error", err.Error())
+	}
+}
+
+func TestParseFileName(t *testing.T) {
+	script, err := Parse("scripts/Chapter-1.NFScript", strings.NewReader("> Hi"))
+	if err != nil || script.Name != "Chapter-1" {
+		t.Errorf("Parse named the script %q, %v, want Chapter-1", script.Name, err)
+	}
+	if _, err := Parse("scripts/Chapter 1.NFScript", strings.NewReader("> Hi")); !errors.Is(err, NFError.ErrScriptParse) {
+		t.Errorf("Parse of a file name with a space returned %v, want an ErrScriptParse", err)
+	}
+	script, err = Parse("scripts/Chapter 1.NFScript", strings.NewReader("# Comment\n\nscene Chapter1\n> Hi"))
+	if err != nil || script.Name != "Chapter1" {
+		t.Errorf("Parse with a scene statement named the script %q, %v, want Chapter1", script.Name, err)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		reason string
+	}{
+		{"no beats", "# Only a comment\nset Global.Gold = 1", "the script has no dialogue"},
+		{"unknown jump label", "> Hi\njump nowhere", "unknown label nowhere"},
+		{"unknown if label", "> Hi\nif true jump nowhere", "unknown label nowhere"},
+		{"unknown option label", "choice\n* Go -> nowhere", "unknown label nowhere"},
+		{"jump loop", "> Hi\nlabel a\nlabel b\njump a", "loops forever"},
+		{"jump loop through two labels", "> Hi\nlabel a\njump b\nlabel b\njump a", "loops forever"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Compile("Test.NFScript", strings.NewReader(test.script))
+			if !errors.Is(err, NFError.ErrScriptParse) || !strings.Contains(err.Error(), test.reason) {
+				t.Errorf("Compile returned %v, want an ErrScriptParse for %q", err, test.reason)
+			}
+		})
+	}
+}
+
+func TestCompileSceneNames(t *testing.T) {
+	scenes := compile(t,
+		"scene Prologue",
+		"label start",
+		"Alice: Hello",
+		"set Global.Gold = 1",
+		"> The wind howls.",
+		"choice",
+		"* Again -> start",
+	)
+	var names []string
+	for _, scene := range scenes {
+		names = append(names, scene.Name)
+	}
+	want := []string{"Prologue_001", "Prologue_002", "Prologue_003"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Compile made scenes %v, want %v", names, want)
+	}
+}
+
+func TestCompileText(t *testing.T) {
+	scenes := compile(t, "Alice: Hello {Global.Player}, it's late", "> Quiet")
+	tests := []struct {
+		scene   *NFScene.Scene
+		text    interface{}
+		speaker string
+	}{
+		{scenes[0], map[string]interface{}{"Expression": `'Hello {Global.Player}, it\'s late'`}, "Alice"},
+		{scenes[1], "Quiet", ""},
+	}
+	for _, test := range tests {
+		box := child(t, test.scene, "Text")
+		var text []interface{}
+		var name string
+		var hasName bool
+		if err := box.Args.Get("AllText", &text); err != nil {
+			t.Fatalf("the text box of %s has no AllText: %v", test.scene.Name, err)
+		}
+		_ = box.Args.Get("Name", &name)
+		_ = box.Args.Get("HasName", &hasName)
+		if !reflect.DeepEqual(text, []interface{}{test.text}) || name != test.speaker || hasName != (test.speaker != "") {
+			t.Errorf("the text box of %s shows %v by %q (%v), want %v by %q", test.scene.Name, text, name, hasName, test.text, test.speaker)
+		}
+	}
+}
+
+func TestCompileContinue(t *testing.T) {
+	tests := []struct {
+		name   string
+		script []string
+		want   map[string]interface{}
+	}{
+		{"next line", []string{"> One", "> Two"}, changeScene("Test_002")},
+		{"last line", []string{"> One"}, nil},
+		{"goto", []string{"> One", "goto MainMenu", "> Skipped"}, changeScene("MainMenu")},
+		{"jump", []string{"label start", "> One", "jump start"}, changeScene("Test_001")},
+		{"jump through labels", []string{"> One", "jump a", "label a", "label b", "> Two"}, changeScene("Test_002")},
+		{"set", []string{"> One", "set Save.Gold = Save.Gold + 1", "> Two"}, call("Sequence", map[string]interface{}{"Functions": []interface{}{
+			call("SetVar", map[string]interface{}{"Location": "Save", "Key": "Gold", "Value": map[string]interface{}{"Expression": "Save.Gold + 1"}}),
+			changeScene("Test_002"),
+		}})},
+		{"if jump", []string{"> One", "if Global.Brave jump fight", "> Run", "label fight", "> Fight"}, call("If", map[string]interface{}{
+			"Condition": map[string]interface{}{"Expression": "Global.Brave"},
+			"Then":      changeScene("Test_003"),
+			"Else":      changeScene("Test_002"),
+		})},
+		{"if goto at the end", []string{"> One", "if Global.Done goto Credits"}, call("If", map[string]interface{}{
+			"Condition": map[string]interface{}{"Expression": "Global.Done"},
+			"Then":      changeScene("Credits"),
+		})},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scenes := compile(t, test.script...)
+			if got := continueCall(t, scenes[0]); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("the Continue button of %s calls %v, want %v", scenes[0].Name, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCompileChoice(t *testing.T) {
+	scenes := compile(t,
+		"choice Where do you go?",
+		"* Go left -> left",
+		"* Go right! -> right if Global.Brave",
+		"* Go left -> left",
+		"* ... -> left",
+		"label left",
+		"> Left",
+		"label right",
+		"> Right",
+	)
+	menu := child(t, scenes[0], "Test_001")
+	var prompt, menuID string
+	var options []interface{}
+	_ = menu.Args.Get("Prompt", &prompt)
+	_ = menu.Args.Get("MenuID", &menuID)
+	if err := menu.Args.Get("Options", &options); err != nil {
+		t.Fatalf("the choice menu has no Options: %v", err)
+	}
+	if prompt != "Where do you go?" || menuID != "Test_001" {
+		t.Errorf("the choice menu has prompt %q and ID %q, want %q and Test_001", prompt, menuID, "Where do you go?")
+	}
+	want := []interface{}{
+		map[string]interface{}{"ID": "go_left", "Text": "Go left", "Function": changeScene("Test_002")},
+		map[string]interface{}{"ID": "go_right", "Text": "Go right!", "Function": changeScene("Test_003"),
+			"Condition": map[string]interface{}{"Expression": "Global.Brave"}},
+		map[string]interface{}{"ID": "option_3", "Text": "Go left", "Function": changeScene("Test_002")},
+		map[string]interface{}{"ID": "option_4", "Text": "...", "Function": changeScene("Test_002")},
+	}
+	if !reflect.DeepEqual(options, want) {
+		t.Errorf("the choice menu has options %v, want %v", options, want)
+	}
+	if got := continueCall(t, scenes[0]); got != nil {
+		t.Errorf("the choice scene has a Continue button that calls %v", got)
+	}
+}
+
+func TestOptionID(t *testing.T) {
+	tests := []struct {
+		text  string
+		index int
+		taken []string
+		want  string
+	}{
+		{"Go left", 0, nil, "go_left"},
+		{"  Go -- LEFT!  ", 0, nil, "go_left"},
+		{"Take 2 coins", 1, nil, "take_2_coins"},
+		{"Été à Paris", 0, nil, "été_à_paris"},
+		{"...", 2, nil, "option_3"},
+		{"Go left", 1, []string{"go_left"}, "option_2"},
+	}
+	for _, test := range tests {
+		if got := optionID(test.text, test.index, test.taken); got != test.want {
+			t.Errorf("optionID(%q, %d, %v) = %q, want %q", test.text, test.index, test.taken, got, test.want)
+		}
+	}
+}
+
+func TestTextValue(t *testing.T) {
+	tests := []struct {
+		text string
+		want interface{}
+	}{
+		{"Plain text", "Plain text"},
+		{"It's {Global.Name}", map[string]interface{}{"Expression": `'It\'s {Global.Name}'`}},
+	}
+	for _, test := range tests {
+		if got := textValue(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("textValue(%q) = %v, want %v", test.text, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package NFData
+
+import (
+	"encoding/json"
+	"errors"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFSave"
+	"reflect"
+)
+
+// The sections of a save that hold GlobalVars and the variables of the active scene
+const (
+	GlobalVarsStateSection     = "GlobalVars"
+	SceneVariablesStateSection = "SceneVariables"
+)
+
+func init() {
+	_ = NFSave.RegisterStateProvider(GlobalVarsStateSection, NFSave.StateFuncs{Save: saveGlobalVars, Load: loadGlobalVars})
+	_ = NFSave.RegisterStateProvider(SceneVariablesStateSection, NFSave.StateFuncs{Save: saveSceneVariables, Load: loadSceneVariables})
+	//Variables change between lines, so rolling back to a line must put them back as well
+	NFSave.KeepForRollback(GlobalVarsStateSection, SceneVariablesStateSection)
+}
+
+// sceneVariablesState is the section of a save that holds the variables of the scene it was written on
+type sceneVariablesState struct {
+	Scene     string                   `json:"Scene"`
+	Variables map[string]savedVariable `json:"Variables"`
+}
+
+// savedVariable is a variable as it is kept in a save, Type is the Go type it is restored as, see variableTypes
+type savedVariable struct {
+	Type  string          `json:"Type,omitempty"`
+	Value json.RawMessage `json:"Value"`
+}
+
+// variableTypes are the types variables are restored as by name, JSON alone would turn an int in to a float64
+// and a map in to a map[string]interface{}, so Get would no longer find them with the type they were set with.
+// Variables of any other type, and the values inside lists and maps, are restored as JSON decodes them
+var variableTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, value := range []interface{}{0, int64(0), float32(0), float64(0), "", false, CustomMap{}, &NFInterfaceMap{}, NFExpression{}} {
+		variableTypes[reflect.TypeOf(value).String()] = reflect.TypeOf(value)
+	}
+}
+
+// saveVariables converts the variables in to the values kept in a save
+func saveVariables(variables *NFInterfaceMap) (map[string]savedVariable, error) {
+	saved := map[string]savedVariable{}
+	for key, value := range variables.Copy().(*NFInterfaceMap).Data {
+		valueBytes, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		variable := savedVariable{Value: valueBytes}
+		if value != nil {
+			if _, ok := variableTypes[reflect.TypeOf(value).String()]; ok {
+				variable.Type = reflect.TypeOf(value).String()
+			}
+		}
+		saved[key] = variable
+	}
+	return saved, nil
+}
+
+// loadVariables converts the values kept in a save back in to variables of the types they were saved with
+func loadVariables(saved map[string]savedVariable) (CustomMap, error) {
+	values := CustomMap{}
+	var err error
+	for key, variable := range saved {
+		valueType, ok := variableTypes[variable.Type]
+		if !ok {
+			var value interface{}
+			err = errors.Join(err, json.Unmarshal(variable.Value, &value))
+			values[key] = value
+			continue
+		}
+		value := reflect.New(valueType)
+		unmarshalError := json.Unmarshal(variable.Value, value.Interface())
+		if unmarshalError != nil {
+			err = errors.Join(err, unmarshalError)
+			continue
+		}
+		values[key] = value.Elem().Interface()
+	}
+	return values, err
+}
+
+func saveGlobalVars() (interface{}, error) {
+	return saveVariables(GlobalVars)
+}
+
+// loadGlobalVars replaces GlobalVars with the values in the save, saves without the section leave them as they are
+func loadGlobalVars(data json.RawMessage) error {
+	if data == nil {
+		return nil
+	}
+	saved := map[string]savedVariable{}
+	err := json.Unmarshal(data, &saved)
+	if err != nil {
+		return err
+	}
+	values, err := loadVariables(saved)
+	return errors.Join(err, replaceVariables(GlobalVars, GlobalBindings, values))
+}
+
+func saveSceneVariables() (interface{}, error) {
+	if ActiveSceneData == nil || ActiveSceneData.Variables == nil {
+		return nil, nil
+	}
+	variables, err := saveVariables(ActiveSceneData.Variables)
+	if err != nil {
+		return nil, err
+	}
+	return sceneVariablesState{Scene: ActiveSceneData.GetSceneName(), Variables: variables}, nil
+}
+
+// loadSceneVariables replaces the variables of the active scene with the values in the save,
+// they are only restored if the active scene is the one the save was written on
+func loadSceneVariables(data json.RawMessage) error {
+	if data == nil || ActiveSceneData == nil || ActiveSceneData.Variables == nil {
+		return nil
+	}
+	var state *sceneVariablesState
+	err := json.Unmarshal(data, &state)
+	if err != nil || state == nil || state.Scene != ActiveSceneData.GetSceneName() {
+		return err
+	}
+	values, err := loadVariables(state.Variables)
+	return errors.Join(err, replaceVariables(ActiveSceneData.Variables, ActiveSceneData.Bindings, values))
+}
+
+// replaceVariables makes the variables hold exactly the values, updating any bindings with the same keys so bound widgets refresh
+func replaceVariables(variables *NFInterfaceMap, bindings *NFBindingMap, values CustomMap) error {
+	var err error
+	for key := range variables.Copy().(*NFInterfaceMap).Data {
+		if _, ok := values[key]; ok {
+			continue
+		}
+		err = errors.Join(err, variables.Delete(key))
+		if bindings != nil {
+			err = errors.Join(err, bindings.UpdateBinding(key, nil))
+		}
+	}
+	for key, value := range values {
+		variables.Set(key, value)
+		if bindings != nil {
+			err = errors.Join(err, bindings.UpdateBinding(key, value))
+		}
+	}
+	return err
+}
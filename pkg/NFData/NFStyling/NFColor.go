@@ -0,0 +1,24 @@
+package NFStyling
+
+import (
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// ParseHexColor parses a #RRGGBB or #RRGGBBAA string in to a color
+func ParseHexColor(hex string) (color.Color, error) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 6 {
+		hex += "FF"
+	}
+	if len(hex) != 8 {
+		return nil, NFError.NewErrInvalidArgument("color", "colors must look like #RRGGBB or #RRGGBBAA")
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, NFError.NewErrInvalidArgument("color", err.Error())
+	}
+	return color.NRGBA{R: uint8(value >> 24), G: uint8(value >> 16), B: uint8(value >> 8), A: uint8(value)}, nil
+}
@@ -0,0 +1,22 @@
+package NFData
+
+import "fyne.io/fyne/v2"
+
+// eventQueue is implemented by the desktop and mobile windows, it runs callbacks one at a time
+// on the goroutine that handles the window's taps and key presses
+type eventQueue interface {
+	QueueEvent(fn func())
+}
+
+// RunOnUI runs f on the goroutine that handles the window's input, after the events before it.
+//
+// Button callbacks, choices and SetVar all change the game state from that goroutine,
+// so timers and other goroutines must use RunOnUI to touch the save, the scene or the variables without racing them.
+// Windows without an event queue, such as test windows or nil, run f straight away
+func RunOnUI(window fyne.Window, f func()) {
+	if queue, ok := window.(eventQueue); ok {
+		queue.QueueEvent(f)
+		return
+	}
+	f()
+}
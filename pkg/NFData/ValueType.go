@@ -1,8 +1,11 @@
 package NFData
 
 import (
+	"fmt"
 	"fyne.io/fyne/v2"
+	"go.novellaforge.dev/novellaforge/pkg/NFData/NFError"
 	"log"
+	"math"
 	"reflect"
 	"strconv"
 )
@@ -117,3 +120,75 @@ func (vt ValueType) Validator() fyne.StringValidator {
 	}
 
 }
+
+// ToFloat converts any numeric value to a float64, returning false if the value is not a number
+func ToFloat(value interface{}) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// ToInt converts any numeric value to an int, floats are only converted if they have no fractional part
+//
+// This is needed as numbers loaded from json are always float64
+func ToInt(value interface{}) (int, bool) {
+	f, ok := ToFloat(value)
+	if !ok || f != math.Trunc(f) {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// ConvertValue converts a value to the given ValueType, strings are parsed and numbers are converted between types
+//
+// Types other than Int, Float, Bool and String are returned unchanged
+func ConvertValue(value interface{}, vt ValueType) (interface{}, error) {
+	switch vt {
+	case IntType:
+		if i, ok := ToInt(value); ok {
+			return i, nil
+		}
+		if str, ok := value.(string); ok {
+			if i, err := strconv.Atoi(str); err == nil {
+				return i, nil
+			}
+		}
+	case FloatType:
+		if f, ok := ToFloat(value); ok {
+			return f, nil
+		}
+		if str, ok := value.(string); ok {
+			if f, err := strconv.ParseFloat(str, 64); err == nil {
+				return f, nil
+			}
+		}
+	case BooleanType:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+		if str, ok := value.(string); ok {
+			if b, err := strconv.ParseBool(str); err == nil {
+				return b, nil
+			}
+		}
+	case StringType:
+		if str, ok := value.(string); ok {
+			return str, nil
+		}
+		return fmt.Sprint(value), nil
+	default:
+		return value, nil
+	}
+	return nil, NFError.NewErrTypeMismatch(vt.String(), fmt.Sprintf("%T", value))
+}